@@ -7,24 +7,319 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"game/api/internal/application"
 	"game/api/internal/application/controller"
 	"game/api/internal/application/repository"
+	"game/api/internal/domain/event"
 	"game/api/internal/domain/service"
 	"game/api/internal/infra/database"
+	"game/api/internal/infra/eventlog"
+	"game/api/internal/infra/hub"
+	"game/api/internal/infra/mail"
 	"game/api/internal/infra/network"
+	"game/api/internal/infra/notify"
+	"game/api/internal/infra/ratelimit"
 	"game/api/internal/infra/session"
+
+	"github.com/google/uuid"
+	cache "github.com/redis/go-redis/v9"
 )
 
-func validateJWTSecret() (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET_KEY")
-	if jwtSecret == "" {
-		return "", fmt.Errorf("JWT_SECRET_KEY environment variable is not set")
+func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return d, nil
+}
+
+func jwtKeyConfig(sessionTTL time.Duration) (session.KeyConfig, error) {
+	config := session.KeyConfig{
+		Algorithm:        os.Getenv("JWT_ALGORITHM"),
+		Secret:           os.Getenv("JWT_SECRET_KEY"),
+		EncryptionSecret: os.Getenv("JWT_KEY_ENCRYPTION_SECRET"),
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = session.AlgorithmHS256
+	}
+	if config.Algorithm == session.AlgorithmHS256 && config.Secret == "" {
+		return config, fmt.Errorf("JWT_SECRET_KEY environment variable is not set")
+	}
+
+	var err error
+	config.RotationInterval, err = durationFromEnv("JWT_KEY_ROTATION", 7*24*time.Hour)
+	if err != nil {
+		return config, err
+	}
+	// o grace nunca pode ser menor que a validade do token
+	config.Grace, err = durationFromEnv("JWT_KEY_GRACE", sessionTTL)
+	if err != nil {
+		return config, err
+	}
+	if config.Grace < sessionTTL {
+		config.Grace = sessionTTL
+	}
+	return config, nil
+}
+
+func sessionBinding() (session.BindingConfig, error) {
+	policy, err := session.ParseBindingPolicy(os.Getenv("SESSION_BINDING"))
+	if err != nil {
+		return session.BindingConfig{}, err
+	}
+	proxies, err := session.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return session.BindingConfig{}, err
+	}
+	return session.BindingConfig{
+		Policy:         policy,
+		TrustedProxies: proxies,
+	}, nil
+}
+
+// wsRouter escolhe como mensagens chegam às conexões WebSocket: redis
+// (padrão) entrega a conexões de qualquer réplica, local apenas às desta.
+func wsRouter(client *cache.Client, connHub *hub.Hub) (hub.Router, error) {
+	switch mode := os.Getenv("WS_BACKPLANE"); mode {
+	case "", "redis":
+		instanceID := os.Getenv("INSTANCE_ID")
+		if instanceID == "" {
+			instanceID, _ = os.Hostname()
+		}
+		if instanceID == "" {
+			instanceID = uuid.NewString()
+		}
+		return hub.NewBackplane(client, connHub, instanceID), nil
+	case "local":
+		return connHub, nil
+	default:
+		return nil, fmt.Errorf("unknown WS_BACKPLANE %q", mode)
+	}
+}
+
+// rateLimitStore escolhe onde ficam os baldes do limite de requisições: redis
+// (padrão) compartilha os limites entre réplicas, memory vale por réplica.
+func rateLimitStore(client *cache.Client) (ratelimit.Store, error) {
+	switch store := os.Getenv("RATE_LIMIT_STORE"); store {
+	case "", "redis":
+		return ratelimit.NewRedis(client), nil
+	case "memory":
+		return ratelimit.NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q", store)
+	}
+}
+
+func rateLimits() (network.RateLimits, error) {
+	limits := network.DefaultRateLimits()
+
+	var err error
+	if limits.Login, err = limitFromEnv("RATE_LIMIT_LOGIN", limits.Login); err != nil {
+		return limits, err
+	}
+	if limits.Register, err = limitFromEnv("RATE_LIMIT_REGISTER", limits.Register); err != nil {
+		return limits, err
+	}
+	if limits.PasswordReset, err = limitFromEnv("RATE_LIMIT_PASSWORD_RESET", limits.PasswordReset); err != nil {
+		return limits, err
+	}
+	if err = limitsFromEnv("RATE_LIMIT_CONNECTION", limits.Connection); err != nil {
+		return limits, err
+	}
+	if err = limitsFromEnv("RATE_LIMIT_CLIENT", limits.Client); err != nil {
+		return limits, err
+	}
+	return limits, nil
+}
+
+func limitFromEnv(name string, fallback ratelimit.Limit) (ratelimit.Limit, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	limit, err := ratelimit.Parse(value)
+	if err != nil {
+		return fallback, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return limit, nil
+}
+
+// limitsFromEnv sobrescreve os limites das ações informadas
+func limitsFromEnv(name string, limits map[string]ratelimit.Limit) error {
+	overrides, err := ratelimit.ParseMap(os.Getenv(name))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	for action, limit := range overrides {
+		limits[action] = limit
+	}
+	return nil
+}
+
+func corsConfig() (network.CORSConfig, error) {
+	config := network.DefaultCORSConfig()
+
+	if value, ok := os.LookupEnv("ALLOWED_ORIGINS"); ok {
+		origins, err := network.ParseOrigins(value)
+		if err != nil {
+			return config, err
+		}
+		config.AllowedOrigins = origins
+	}
+	var err error
+	if config.MaxAge, err = durationFromEnv("CORS_MAX_AGE", config.MaxAge); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+func eventLogConfig() (eventlog.Config, error) {
+	config := eventlog.DefaultConfig()
+
+	size, err := intFromEnv("EVENT_BUFFER_SIZE", int(config.Size))
+	if err != nil {
+		return config, err
+	}
+	config.Size = int64(size)
+	if config.TTL, err = durationFromEnv("EVENT_BUFFER_TTL", config.TTL); err != nil {
+		return config, err
+	}
+	if config.MaxFollowers, err = intFromEnv("SSE_MAX_STREAMS", config.MaxFollowers); err != nil {
+		return config, err
+	}
+	if config.Size <= 0 || config.MaxFollowers <= 0 {
+		return config, fmt.Errorf("EVENT_BUFFER_SIZE and SSE_MAX_STREAMS must be positive")
+	}
+	return config, nil
+}
+
+// eventLogConn abre um pool separado para o buffer de eventos, já que cada
+// stream SSE mantém uma leitura bloqueante no Redis
+func eventLogConn(client *cache.Client, config eventlog.Config) *cache.Client {
+	opts := *client.Options()
+	opts.PoolSize = config.MaxFollowers + 10
+	// cancela a leitura bloqueante quando o stream é fechado
+	opts.ContextTimeoutEnabled = true
+	return cache.NewClient(&opts)
+}
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "Game"
+}
+
+func intFromEnv(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return n, nil
+}
+
+func floatFromEnv(name string, fallback float64) (float64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return f, nil
+}
+
+func credentialsPolicyFromEnv() (service.CredentialsPolicy, error) {
+	policy := service.DefaultCredentialsPolicy()
+
+	var err error
+	if policy.UsernameMinLength, err = intFromEnv("USERNAME_MIN_LENGTH", policy.UsernameMinLength); err != nil {
+		return policy, err
+	}
+	if policy.UsernameMaxLength, err = intFromEnv("USERNAME_MAX_LENGTH", policy.UsernameMaxLength); err != nil {
+		return policy, err
+	}
+	// a coluna username é VARCHAR(60)
+	if policy.UsernameMaxLength > 60 {
+		policy.UsernameMaxLength = 60
+	}
+	if pattern := os.Getenv("USERNAME_PATTERN"); pattern != "" {
+		if policy.UsernamePattern, err = regexp.Compile(pattern); err != nil {
+			return policy, fmt.Errorf("invalid USERNAME_PATTERN: %v", err)
+		}
+	}
+	if reserved := os.Getenv("RESERVED_USERNAMES"); reserved != "" {
+		policy.ReservedUsernames = service.NewNameSet(strings.Split(reserved, ",")...)
+	}
+	if policy.PasswordMinLength, err = intFromEnv("PASSWORD_MIN_LENGTH", policy.PasswordMinLength); err != nil {
+		return policy, err
+	}
+	if path := os.Getenv("BREACHED_PASSWORDS_FILE"); path != "" {
+		if policy.BreachedPasswords, err = service.LoadBreachedPasswords(path); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+func mailTransportFromEnv() mail.Transport {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+
+	switch os.Getenv("MAIL_TRANSPORT") {
+	case "smtp":
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return mail.NewSMTPTransport(mail.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		})
+	case "file":
+		path := os.Getenv("MAIL_FILE")
+		if path == "" {
+			path = "mail.log"
+		}
+		return mail.NewFileTransport(path, from)
+	default:
+		return mail.NewLogTransport()
+	}
+}
+
+func notifierFromEnv(transport mail.Transport) notify.Notifier {
+	switch os.Getenv("NOTIFIER") {
+	case "mail":
+		return notify.NewMailNotifier(transport)
+	case "file":
+		path := os.Getenv("NOTIFIER_FILE")
+		if path == "" {
+			path = "notifications.log"
+		}
+		return notify.NewFileNotifier(path)
+	default:
+		return notify.NewLogNotifier()
 	}
-	return jwtSecret, nil
 }
 
 func main() {
@@ -45,26 +340,180 @@ func main() {
 
 	redis := database.NewRedis(redisConn)
 
-	jwtSecret, err := validateJWTSecret()
+	sessionTTL := 24 * time.Hour
+	keyConfig, err := jwtKeyConfig(sessionTTL)
 	if err != nil {
-		log.Fatalf("ERROR validating JWT secret: %v", err)
+		log.Fatalf("ERROR validating JWT configuration: %v", err)
 	}
+	keySet, err := session.NewKeySet(ctx, redisConn, keyConfig)
+	if err != nil {
+		log.Fatalf("ERROR loading JWT signing keys: %v", err)
+	}
+
+	bgCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+	go keySet.Run(bgCtx)
+
+	binding, err := sessionBinding()
+	if err != nil {
+		log.Fatalf("ERROR validating session binding configuration: %v", err)
+	}
+	sessionManager := session.NewManager(redisConn, sessionTTL, keySet, binding)
 
-	sessionManager := session.NewManager(redisConn, 24*time.Hour, jwtSecret)
+	events := event.NewBus()
+	sessionManager.OnRevoke(func(ctx context.Context, clientID string, tokens []string) {
+		id, err := uuid.Parse(clientID)
+		if err != nil {
+			return
+		}
+		events.Publish(ctx, event.SessionRevoked, id, event.SessionRevokedPayload{Tokens: tokens})
+	})
 
 	clientsRepo := repository.NewClients(redis, db)
 	walletRepo := repository.NewWallets(redis, db)
 	playerRepo := repository.NewPlayers(redis, clientsRepo, walletRepo)
+	loginAttemptsRepo := repository.NewLoginAttempts(redis, db)
+	twoFactorRepo := repository.NewTwoFactor(redis, db)
+	passwordResetsRepo := repository.NewPasswordResets(redis)
+	emailVerificationsRepo := repository.NewEmailVerifications(redis)
+	betsRepo := repository.NewBets(db)
+	adjustmentsRepo := repository.NewAdjustments(db)
+	ledgerRepo := repository.NewLedger(db)
+	apiKeysRepo := repository.NewAPIKeys(redis, db)
+
+	sessionManager.UseMFAStatus(func(ctx context.Context, clientID string) (bool, error) {
+		id, err := uuid.Parse(clientID)
+		if err != nil {
+			return false, err
+		}
+		client, err := clientsRepo.Get(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		return client.TOTPEnabled(), nil
+	})
+
+	credentialsPolicy, err := credentialsPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("ERROR validating credentials policy: %v", err)
+	}
+	resetTTL, err := durationFromEnv("PASSWORD_RESET_TTL", 30*time.Minute)
+	if err != nil {
+		log.Fatalf("ERROR validating password reset configuration: %v", err)
+	}
+	verificationTTL, err := durationFromEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+	if err != nil {
+		log.Fatalf("ERROR validating email verification configuration: %v", err)
+	}
+	approvalThreshold, err := floatFromEnv("ADJUSTMENT_APPROVAL_THRESHOLD", 1000)
+	if err != nil {
+		log.Fatalf("ERROR validating balance adjustment configuration: %v", err)
+	}
+	hubConfig := hub.DefaultConfig()
+	hubConfig.SendBuffer, err = intFromEnv("WS_SEND_BUFFER", hubConfig.SendBuffer)
+	if err != nil {
+		log.Fatalf("ERROR validating WebSocket configuration: %v", err)
+	}
+	mailTransport := mailTransportFromEnv()
+
+	emailService := service.NewEmailService(
+		clientsRepo,
+		emailVerificationsRepo,
+		mailTransport,
+		verificationTTL,
+		os.Getenv("EMAIL_VERIFICATION_URL"),
+	)
+	clientsService := service.NewClientService(clientsRepo, walletRepo, emailService, credentialsPolicy)
+	matchService := service.NewMatchService(clientsRepo, playerRepo, walletRepo, emailService, events)
+	twoFactorService := service.NewTwoFactorService(clientsRepo, twoFactorRepo, totpIssuer())
+	authService := service.NewAuthService(clientsService, twoFactorService, sessionManager, loginAttemptsRepo, service.DefaultLoginPolicy())
+	passwordService := service.NewPasswordService(
+		clientsRepo,
+		passwordResetsRepo,
+		loginAttemptsRepo,
+		sessionManager,
+		notifierFromEnv(mailTransport),
+		credentialsPolicy,
+		resetTTL,
+		os.Getenv("PASSWORD_RESET_URL"),
+	)
+	apiKeyService := service.NewAPIKeyService(apiKeysRepo, clientsRepo, events, service.DefaultAPIKeyPolicy())
+	sessionManager.UseAPIKeys(apiKeyService)
+	accountService := service.NewAccountService(clientsRepo, playerRepo, apiKeysRepo, sessionManager, events)
+	privacyService := service.NewPrivacyService(
+		clientsRepo,
+		walletRepo,
+		betsRepo,
+		ledgerRepo,
+		accountService,
+		sessionManager,
+	)
+	connHub := hub.New(hubConfig)
+	router, err := wsRouter(redisConn, connHub)
+	if err != nil {
+		log.Fatalf("ERROR validating WebSocket configuration: %v", err)
+	}
+	if backplane, ok := router.(*hub.Backplane); ok {
+		go backplane.Run(bgCtx)
+	}
+	logConfig, err := eventLogConfig()
+	if err != nil {
+		log.Fatalf("ERROR validating event buffer configuration: %v", err)
+	}
+	eventsConn := eventLogConn(redisConn, logConfig)
+	defer eventsConn.Close()
+	eventLog := eventlog.New(eventsConn, logConfig)
 
-	clientsService := service.NewClientService(clientsRepo, walletRepo)
-	matchService := service.NewMatchService(playerRepo, walletRepo)
-	authService := service.NewAuthService(clientsService, sessionManager)
+	limiter, err := rateLimitStore(redisConn)
+	if err != nil {
+		log.Fatalf("ERROR validating rate limit configuration: %v", err)
+	}
+	limits, err := rateLimits()
+	if err != nil {
+		log.Fatalf("ERROR validating rate limit configuration: %v", err)
+	}
+	cors, err := corsConfig()
+	if err != nil {
+		log.Fatalf("ERROR validating CORS configuration: %v", err)
+	}
+	adminService := service.NewAdminService(
+		clientsRepo,
+		walletRepo,
+		playerRepo,
+		betsRepo,
+		adjustmentsRepo,
+		accountService,
+		privacyService,
+		sessionManager,
+		events,
+		approvalThreshold,
+	)
 
-	clientsCtrl := controller.NewClientController(clientsService)
-	authCtrl := controller.NewAuthController(authService)
+	clientsCtrl := controller.NewClientController(clientsService, emailService, accountService)
+	authCtrl := controller.NewAuthController(authService, twoFactorService)
 	matchCtrl := controller.NewMatchController(matchService)
+	passwordCtrl := controller.NewPasswordController(passwordService)
+	adminCtrl := controller.NewAdminController(adminService)
+	privacyCtrl := controller.NewPrivacyController(privacyService)
+	apiKeyCtrl := controller.NewAPIKeyController(apiKeyService)
 
-	api := network.NewWebServer(clientsCtrl, authCtrl, matchCtrl, sessionManager)
+	api := network.NewWebServer(
+		clientsCtrl,
+		authCtrl,
+		matchCtrl,
+		passwordCtrl,
+		adminCtrl,
+		privacyCtrl,
+		apiKeyCtrl,
+		sessionManager,
+		connHub,
+		router,
+		eventLog,
+		limiter,
+		limits,
+		cors,
+	)
+	events.Subscribe(api.PushEvent)
 	mux := http.NewServeMux()
 	mux.Handle("/", api)
 
@@ -72,6 +521,7 @@ func main() {
 		Addr:    ":8000",
 		Handler: mux,
 	}
+	server.RegisterOnShutdown(api.CloseStreams)
 
 	//canal para receber sinais do o.s
 	stop := make(chan os.Signal, 1)
@@ -88,6 +538,7 @@ func main() {
 	//sinal de interrupção
 	<-stop
 	log.Println("Shutting down server...")
+	stopBackground()
 
 	//timeout para o shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -97,6 +548,7 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Error during server shutdown: %v", err)
 	}
+	connHub.Shutdown()
 
 	log.Println("Server gracefully stopped")
 }
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
+	"game/api/internal/domain/service"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+type AdminController struct {
+	adminService *service.AdminService
+}
+
+func NewAdminController(adminService *service.AdminService) *AdminController {
+	return &AdminController{
+		adminService: adminService,
+	}
+}
+
+func adminClientResponse(client entity.Client) dto.AdminClientResponse {
+	return dto.AdminClientResponse{
+		ID:            client.GetID().String(),
+		Username:      client.GetUsername(),
+		Email:         client.GetEmail(),
+		EmailVerified: client.EmailVerified(),
+		Role:          string(client.GetRole()),
+		Suspended:     client.Suspended(),
+		MFAEnabled:    client.TOTPEnabled(),
+		CreatedAt:     client.GetCreatedAt(),
+	}
+}
+
+func (c *AdminController) SearchClients(ctx context.Context, term string, limit, offset int) (res dto.AdminClientListResponse, err error) {
+	clients, err := c.adminService.SearchClients(ctx, term, limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to search clients: %v", err)
+		return
+	}
+
+	res.Clients = make([]dto.AdminClientResponse, 0, len(clients))
+	for _, client := range clients {
+		res.Clients = append(res.Clients, adminClientResponse(client))
+	}
+	return
+}
+
+func (c *AdminController) GetClient(ctx context.Context, clientID string) (res dto.AdminClientResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return res, errs.ErrNotFound
+	}
+
+	client, err := c.adminService.GetClient(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to get client: %v", err)
+		return
+	}
+	return adminClientResponse(client), nil
+}
+
+func (c *AdminController) GetWallet(ctx context.Context, clientID string) (res dto.AdminWalletResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return res, errs.ErrNotFound
+	}
+
+	wallet, err := c.adminService.GetWallet(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to get wallet: %v", err)
+		return
+	}
+	return dto.AdminWalletResponse{
+		ClientID: wallet.ClientID.String(),
+		Balance:  wallet.Balance,
+	}, nil
+}
+
+func (c *AdminController) ListBets(ctx context.Context, clientID string, limit, offset int) (res dto.AdminBetListResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return res, errs.ErrNotFound
+	}
+
+	bets, err := c.adminService.ListBets(ctx, clientUUID, limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to list bets: %v", err)
+		return
+	}
+
+	res.Bets = make([]dto.BetResponse, 0, len(bets))
+	for _, bet := range bets {
+		res.Bets = append(res.Bets, dto.BetResponse{
+			ID:           bet.ID.String(),
+			Amount:       bet.Amount,
+			Choice:       bet.Choice,
+			Number:       bet.Number,
+			Result:       bet.Result,
+			BalanceAfter: bet.BalanceAfter,
+			CreatedAt:    bet.CreatedAt,
+		})
+	}
+	return
+}
+
+func (c *AdminController) Suspend(ctx context.Context, adminID, clientID string) error {
+	return c.clientAction(ctx, adminID, clientID, "suspend client", c.adminService.Suspend)
+}
+
+func (c *AdminController) Unsuspend(ctx context.Context, adminID, clientID string) error {
+	return c.clientAction(ctx, adminID, clientID, "unsuspend client", c.adminService.Unsuspend)
+}
+
+func (c *AdminController) Close(ctx context.Context, adminID, clientID string) error {
+	return c.clientAction(ctx, adminID, clientID, "close client account", c.adminService.Close)
+}
+
+func (c *AdminController) Erase(ctx context.Context, adminID, clientID string) error {
+	return c.clientAction(ctx, adminID, clientID, "erase client data", c.adminService.EraseClient)
+}
+
+func (c *AdminController) Export(ctx context.Context, clientID string) (res dto.DataExportResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return res, errs.ErrNotFound
+	}
+
+	export, err := c.adminService.ExportClient(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to export client data: %v", err)
+		return
+	}
+	return dataExportResponse(export), nil
+}
+
+func (c *AdminController) clientAction(
+	ctx context.Context,
+	adminID, clientID, action string,
+	fn func(ctx context.Context, adminID, clientID uuid.UUID) error,
+) error {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		logger.Errorf("Failed to parse adminID: %v", err)
+		return err
+	}
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	err = fn(ctx, adminUUID, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to %s: %v", action, err)
+		return err
+	}
+	return nil
+}
+
+func (c *AdminController) SetRole(ctx context.Context, adminID, clientID, role string) error {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		logger.Errorf("Failed to parse adminID: %v", err)
+		return err
+	}
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+	err = c.adminService.SetRole(ctx, adminUUID, clientUUID, role)
+	if err != nil {
+		logger.Errorf("Failed to set client role: %v", err)
+		return err
+	}
+	return nil
+}
+
+func adjustmentResponse(adj entity.BalanceAdjustment) dto.AdjustmentResponse {
+	res := dto.AdjustmentResponse{
+		ID:          adj.ID.String(),
+		ClientID:    adj.ClientID.String(),
+		Amount:      adj.Amount,
+		Reason:      adj.Reason,
+		Status:      string(adj.Status),
+		RequestedBy: adj.RequestedBy.String(),
+		CreatedAt:   adj.CreatedAt,
+		DecidedAt:   adj.DecidedAt,
+	}
+	if adj.DecidedBy != nil {
+		res.DecidedBy = adj.DecidedBy.String()
+	}
+	return res
+}
+
+func (c *AdminController) AdjustBalance(ctx context.Context, adminID, clientID string, amount float64, reason string) (res dto.AdjustmentResponse, err error) {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		logger.Errorf("Failed to parse adminID: %v", err)
+		return
+	}
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return res, errs.ErrNotFound
+	}
+
+	adj, err := c.adminService.AdjustBalance(ctx, adminUUID, clientUUID, amount, reason)
+	if err != nil {
+		logger.Errorf("Failed to adjust balance: %v", err)
+		return
+	}
+	return adjustmentResponse(adj), nil
+}
+
+func (c *AdminController) ListAdjustments(ctx context.Context, status string, limit, offset int) (res dto.AdjustmentListResponse, err error) {
+	adjustments, err := c.adminService.ListAdjustments(ctx, status, limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to list balance adjustments: %v", err)
+		return
+	}
+
+	res.Adjustments = make([]dto.AdjustmentResponse, 0, len(adjustments))
+	for _, adj := range adjustments {
+		res.Adjustments = append(res.Adjustments, adjustmentResponse(adj))
+	}
+	return
+}
+
+func (c *AdminController) ApproveAdjustment(ctx context.Context, adminID, adjustmentID string) (res dto.AdjustmentResponse, err error) {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		logger.Errorf("Failed to parse adminID: %v", err)
+		return
+	}
+	adjustmentUUID, err := uuid.Parse(adjustmentID)
+	if err != nil {
+		return res, errs.ErrNotFound
+	}
+
+	adj, err := c.adminService.ApproveAdjustment(ctx, adminUUID, adjustmentUUID)
+	if err != nil {
+		logger.Errorf("Failed to approve balance adjustment: %v", err)
+		return
+	}
+	return adjustmentResponse(adj), nil
+}
+
+func (c *AdminController) RejectAdjustment(ctx context.Context, adminID, adjustmentID string) error {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		logger.Errorf("Failed to parse adminID: %v", err)
+		return err
+	}
+	adjustmentUUID, err := uuid.Parse(adjustmentID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	err = c.adminService.RejectAdjustment(ctx, adminUUID, adjustmentUUID)
+	if err != nil {
+		logger.Errorf("Failed to reject balance adjustment: %v", err)
+		return err
+	}
+	return nil
+}
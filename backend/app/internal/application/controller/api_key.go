@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
+	"game/api/internal/domain/service"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+type APIKeyController struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyController(apiKeyService *service.APIKeyService) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func apiKeyResponse(key entity.APIKey) dto.APIKeyResponse {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return dto.APIKeyResponse{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		RateLimit: key.RateLimit,
+		CreatedAt: key.CreatedAt,
+	}
+}
+
+func (c *APIKeyController) Create(ctx context.Context, clientID string, req dto.CreateAPIKeyRequest) (res dto.CreateAPIKeyResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return
+	}
+
+	secret, key, err := c.apiKeyService.Create(ctx, clientUUID, req.Name, req.Scopes, req.RateLimit)
+	if err != nil {
+		logger.Errorf("Failed to create API key: %v", err)
+		return
+	}
+	return dto.CreateAPIKeyResponse{
+		APIKeyResponse: apiKeyResponse(key),
+		Key:            secret,
+	}, nil
+}
+
+func (c *APIKeyController) List(ctx context.Context, clientID string) (res dto.APIKeyListResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return
+	}
+
+	keys, err := c.apiKeyService.List(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to list API keys: %v", err)
+		return
+	}
+
+	res.Keys = make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		res.Keys = append(res.Keys, apiKeyResponse(key))
+	}
+	return
+}
+
+func (c *APIKeyController) Revoke(ctx context.Context, clientID, keyID string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+	keyUUID, err := uuid.Parse(keyID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+
+	err = c.apiKeyService.Revoke(ctx, clientUUID, keyUUID)
+	if err != nil {
+		logger.Errorf("Failed to revoke API key: %v", err)
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"game/api/internal/application/dto"
 	"game/api/internal/domain/service"
 	"game/api/internal/infra/logger"
 	"game/api/internal/infra/session"
@@ -12,26 +13,152 @@ import (
 )
 
 type AuthController struct {
-	authService *service.AuthService
+	authService      *service.AuthService
+	twoFactorService *service.TwoFactorService
 }
 
-func NewAuthController(authService *service.AuthService) *AuthController {
+func NewAuthController(authService *service.AuthService, twoFactorService *service.TwoFactorService) *AuthController {
 	return &AuthController{
-		authService: authService,
+		authService:      authService,
+		twoFactorService: twoFactorService,
 	}
 }
 
-func (c *AuthController) Login(ctx context.Context, username, password string) (string, error) {
+func (c *AuthController) Login(ctx context.Context, username, password string) (res dto.ClientLoginResponse, err error) {
 	ip := ctx.Value(session.ContextKeyIP).(string)
 	userAgent := ctx.Value(session.ContextKeyUserAgent).(string)
 
-	token, err := c.authService.Login(ctx, username, password, ip, userAgent)
+	result, err := c.authService.Login(ctx, username, password, ip, userAgent)
 	if err != nil {
 		logger.Errorf("Failed to login: %v", err)
-		return "", err
+		return
 	}
 
-	return token, nil
+	res = dto.ClientLoginResponse{
+		Token:       result.Token,
+		MFARequired: result.MFARequired,
+	}
+	return
+}
+
+func (c *AuthController) VerifyMFA(ctx context.Context, partialToken, code string) (res dto.ClientLoginResponse, err error) {
+	token, err := c.authService.VerifyMFA(ctx, partialToken, code)
+	if err != nil {
+		logger.Errorf("Failed to verify two-factor code: %v", err)
+		return
+	}
+
+	res = dto.ClientLoginResponse{
+		Token: token,
+	}
+	return
+}
+
+func (c *AuthController) ConfirmMFA(ctx context.Context, clientID, token, code string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+	ip := ctx.Value(session.ContextKeyIP).(string)
+	userAgent := ctx.Value(session.ContextKeyUserAgent).(string)
+	return c.authService.ConfirmMFA(ctx, clientUUID, token, code, ip, userAgent)
+}
+
+func (c *AuthController) Reauthenticate(ctx context.Context, clientID, token, password, code string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+	ip := ctx.Value(session.ContextKeyIP).(string)
+	userAgent := ctx.Value(session.ContextKeyUserAgent).(string)
+
+	err = c.authService.Reauthenticate(ctx, clientUUID, token, password, code, ip, userAgent)
+	if err != nil {
+		logger.Errorf("Failed to re-authenticate: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (c *AuthController) EnrollMFA(ctx context.Context, clientID string) (res dto.MFAEnrollResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return
+	}
+
+	secret, uri, err := c.twoFactorService.Enroll(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to enroll two-factor: %v", err)
+		return
+	}
+
+	res = dto.MFAEnrollResponse{
+		Secret: secret,
+		URI:    uri,
+	}
+	return
+}
+
+func (c *AuthController) EnableMFA(ctx context.Context, clientID, code string) (res dto.MFARecoveryCodesResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return
+	}
+
+	codes, err := c.twoFactorService.Enable(ctx, clientUUID, code)
+	if err != nil {
+		logger.Errorf("Failed to enable two-factor: %v", err)
+		return
+	}
+
+	res = dto.MFARecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+	return
+}
+
+func (c *AuthController) DisableMFA(ctx context.Context, clientID, code string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+
+	ip := ctx.Value(session.ContextKeyIP).(string)
+	userAgent := ctx.Value(session.ContextKeyUserAgent).(string)
+
+	err = c.authService.DisableMFA(ctx, clientUUID, code, ip, userAgent)
+	if err != nil {
+		logger.Errorf("Failed to disable two-factor: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (c *AuthController) RegenerateRecoveryCodes(ctx context.Context, clientID, code string) (res dto.MFARecoveryCodesResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return
+	}
+
+	ip := ctx.Value(session.ContextKeyIP).(string)
+	userAgent := ctx.Value(session.ContextKeyUserAgent).(string)
+
+	codes, err := c.authService.RegenerateRecoveryCodes(ctx, clientUUID, code, ip, userAgent)
+	if err != nil {
+		logger.Errorf("Failed to regenerate recovery codes: %v", err)
+		return
+	}
+
+	res = dto.MFARecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+	return
 }
 
 func (c *AuthController) Logout(ctx context.Context, clientID, token string) error {
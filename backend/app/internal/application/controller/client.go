@@ -2,25 +2,49 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 
 	"game/api/internal/application/dto"
 	"game/api/internal/domain/service"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
 )
 
 type ClientController struct {
-	clientService *service.ClientService
+	clientService  *service.ClientService
+	emailService   *service.EmailService
+	accountService *service.AccountService
 }
 
-func NewClientController(clientService *service.ClientService) *ClientController {
+func NewClientController(
+	clientService *service.ClientService,
+	emailService *service.EmailService,
+	accountService *service.AccountService,
+) *ClientController {
 	return &ClientController{
-		clientService: clientService,
+		clientService:  clientService,
+		emailService:   emailService,
+		accountService: accountService,
 	}
 }
 
-func (c *ClientController) Create(ctx context.Context, username, password string) (res dto.CreateClientResponse, err error) {
-	clientID, err := c.clientService.Create(ctx, username, password)
+func (c *ClientController) Create(ctx context.Context, username, email, password string) (res dto.CreateClientResponse, err error) {
+	clientID, err := c.clientService.Create(ctx, username, email, password)
+	if errors.Is(err, errs.ErrUsernameExists) {
+		v := &errs.ValidationError{}
+		v.Add(service.FieldUsername, service.CodeTaken, "is already taken")
+		err = fmt.Errorf("%w: %w", errs.ErrUsernameExists, v)
+		return
+	}
+	if errors.Is(err, errs.ErrEmailExists) {
+		v := &errs.ValidationError{}
+		v.Add(service.FieldEmail, service.CodeTaken, "is already taken")
+		err = fmt.Errorf("%w: %w", errs.ErrEmailExists, v)
+		return
+	}
 	if err != nil {
 		return
 	}
@@ -50,3 +74,85 @@ func (c *ClientController) GetBalance(ctx context.Context, clientID string) (res
 	}
 	return
 }
+
+func (c *ClientController) VerifyEmail(ctx context.Context, token string) error {
+	err := c.emailService.Verify(ctx, token)
+	if err != nil {
+		logger.Errorf("Failed to verify email: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (c *ClientController) ResendVerification(ctx context.Context, clientID string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+
+	err = c.emailService.Resend(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to resend verification email: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (c *ClientController) ChangeEmail(ctx context.Context, clientID, email string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+
+	err = c.emailService.Change(ctx, clientUUID, email)
+	if errors.Is(err, errs.ErrEmailExists) {
+		v := &errs.ValidationError{}
+		v.Add(service.FieldEmail, service.CodeTaken, "is already taken")
+		return fmt.Errorf("%w: %w", errs.ErrEmailExists, v)
+	}
+	if err != nil {
+		logger.Errorf("Failed to change email: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ValidationErrorResponse converte o erro de validação no corpo retornado
+// ao cliente; retorna false se err não é um erro de validação.
+func ValidationErrorResponse(err error) (res dto.ValidationErrorResponse, ok bool) {
+	var v *errs.ValidationError
+	if !errors.As(err, &v) {
+		return
+	}
+
+	res = dto.ValidationErrorResponse{
+		Error:   "validation_failed",
+		Message: v.Error(),
+		Fields:  make([]dto.FieldError, 0, len(v.Fields)),
+	}
+	for _, f := range v.Fields {
+		res.Fields = append(res.Fields, dto.FieldError{
+			Field:   f.Field,
+			Code:    f.Code,
+			Message: f.Message,
+		})
+	}
+	return res, true
+}
+
+func (c *ClientController) CloseAccount(ctx context.Context, clientID, password string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+
+	err = c.accountService.CloseOwn(ctx, clientUUID, password)
+	if err != nil {
+		logger.Errorf("Failed to close account: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
+	"game/api/internal/domain/event"
+)
+
+// EventPayload converte o evento de domínio no corpo enviado ao cliente;
+// retorna false para eventos que não são entregues a clientes.
+func EventPayload(e event.Event) (payload interface{}, ok bool) {
+	switch p := e.Payload.(type) {
+	case entity.Wallet:
+		return dto.BalanceUpdatedEvent{Balance: p.Balance}, true
+	case entity.Bet:
+		return dto.BetSettledEvent{
+			Bet: dto.BetResponse{
+				ID:           p.ID.String(),
+				Amount:       p.Amount,
+				Choice:       p.Choice,
+				Number:       p.Number,
+				Result:       p.Result,
+				BalanceAfter: p.BalanceAfter,
+				CreatedAt:    p.CreatedAt,
+			},
+		}, true
+	case entity.Player:
+		return dto.MatchEndedEvent{Balance: p.Balance}, true
+	case event.SessionRevokedPayload:
+		return dto.SessionRevokedEvent{RevokedAt: e.OccurredAt}, true
+	case event.APIKeyRevokedPayload:
+		return dto.APIKeyRevokedEvent{KeyID: p.KeyID, RevokedAt: e.OccurredAt}, true
+	}
+	return nil, false
+}
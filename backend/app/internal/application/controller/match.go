@@ -7,6 +7,7 @@ import (
 
 	"game/api/internal/application/dto"
 	"game/api/internal/domain/service"
+	"game/api/internal/errs"
 	"game/api/internal/infra/logger"
 )
 
@@ -20,19 +21,34 @@ func NewMatchController(serviceMatch *service.MatchService) *MatchController {
 	}
 }
 
-func (c *MatchController) NewMatch(ctx context.Context, playerID string) error {
+func (c *MatchController) NewMatch(ctx context.Context, playerID string) (response dto.MatchResponse, err error) {
 	playerUUID, err := uuid.Parse(playerID)
 	if err != nil {
 		logger.Errorf("Failed to parse playerID: %v", err)
-		return err
+		return
 	}
 
-	err = c.serviceMatch.NewMatch(ctx, playerUUID)
+	matchID, err := c.serviceMatch.NewMatch(ctx, playerUUID)
 	if err != nil {
 		logger.Errorf("Failed to new game: %v", err)
+		return
+	}
+	return dto.MatchResponse{
+		MatchID: matchID.String(),
+	}, nil
+}
+
+func (c *MatchController) RequireMatch(ctx context.Context, playerID, matchID string) error {
+	playerUUID, err := uuid.Parse(playerID)
+	if err != nil {
+		logger.Errorf("Failed to parse playerID: %v", err)
 		return err
 	}
-	return nil
+	matchUUID, err := uuid.Parse(matchID)
+	if err != nil {
+		return errs.ErrNotFound
+	}
+	return c.serviceMatch.RequireMatch(ctx, playerUUID, matchUUID)
 }
 
 func (c *MatchController) Bet(ctx context.Context, playerID string, amount float64, choice string) (response dto.PlaceBetResponse, err error) {
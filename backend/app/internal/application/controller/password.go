@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/domain/service"
+	"game/api/internal/infra/logger"
+)
+
+type PasswordController struct {
+	passwordService *service.PasswordService
+}
+
+func NewPasswordController(passwordService *service.PasswordService) *PasswordController {
+	return &PasswordController{
+		passwordService: passwordService,
+	}
+}
+
+func (c *PasswordController) Change(ctx context.Context, clientID, token, currentPassword, newPassword string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+
+	err = c.passwordService.Change(ctx, clientUUID, token, currentPassword, newPassword)
+	if err != nil {
+		logger.Errorf("Failed to change password: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (c *PasswordController) Forgot(ctx context.Context, username string) error {
+	err := c.passwordService.RequestReset(ctx, username)
+	if err != nil {
+		logger.Errorf("Failed to request password reset: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (c *PasswordController) Reset(ctx context.Context, token, newPassword string) error {
+	err := c.passwordService.Reset(ctx, token, newPassword)
+	if err != nil {
+		logger.Errorf("Failed to reset password: %v", err)
+		return err
+	}
+	return nil
+}
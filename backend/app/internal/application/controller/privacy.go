@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/service"
+	"game/api/internal/infra/logger"
+)
+
+type PrivacyController struct {
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyController(privacyService *service.PrivacyService) *PrivacyController {
+	return &PrivacyController{
+		privacyService: privacyService,
+	}
+}
+
+func dataExportResponse(export service.DataExport) dto.DataExportResponse {
+	client := export.Client
+	res := dto.DataExportResponse{
+		GeneratedAt: export.GeneratedAt,
+		Profile: dto.ExportProfile{
+			ID:            client.GetID().String(),
+			Username:      client.GetUsername(),
+			Email:         client.GetEmail(),
+			EmailVerified: client.EmailVerified(),
+			Role:          string(client.GetRole()),
+			MFAEnabled:    client.TOTPEnabled(),
+			CreatedAt:     client.GetCreatedAt(),
+		},
+		Wallet:       dto.GetBalanceResponse{Balance: export.Wallet.Balance},
+		Sessions:     make([]dto.ExportSession, 0, len(export.Sessions)),
+		Bets:         make([]dto.BetResponse, 0, len(export.Bets)),
+		Transactions: make([]dto.ExportTransaction, 0, len(export.Transactions)),
+	}
+
+	for _, sess := range export.Sessions {
+		res.Sessions = append(res.Sessions, dto.ExportSession{
+			IP:           sess.IP,
+			UserAgent:    sess.UserAgent,
+			CreatedAt:    sess.CreatedAt,
+			LastActivity: sess.LastActivity,
+		})
+	}
+	for _, bet := range export.Bets {
+		res.Bets = append(res.Bets, dto.BetResponse{
+			ID:           bet.ID.String(),
+			Amount:       bet.Amount,
+			Choice:       bet.Choice,
+			Number:       bet.Number,
+			Result:       bet.Result,
+			BalanceAfter: bet.BalanceAfter,
+			CreatedAt:    bet.CreatedAt,
+		})
+	}
+	for _, entry := range export.Transactions {
+		res.Transactions = append(res.Transactions, dto.ExportTransaction{
+			ID:           entry.ID.String(),
+			Type:         string(entry.Type),
+			Amount:       entry.Amount,
+			BalanceAfter: entry.BalanceAfter,
+			ReferenceID:  entry.ReferenceID.String(),
+			Reason:       entry.Reason,
+			CreatedAt:    entry.CreatedAt,
+		})
+	}
+	return res
+}
+
+func (c *PrivacyController) Export(ctx context.Context, clientID string) (res dto.DataExportResponse, err error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return
+	}
+
+	export, err := c.privacyService.Export(ctx, clientUUID)
+	if err != nil {
+		logger.Errorf("Failed to export client data: %v", err)
+		return
+	}
+	return dataExportResponse(export), nil
+}
+
+func (c *PrivacyController) Erase(ctx context.Context, clientID, password string) error {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		logger.Errorf("Failed to parse clientID: %v", err)
+		return err
+	}
+
+	err = c.privacyService.EraseOwn(ctx, clientUUID, password)
+	if err != nil {
+		logger.Errorf("Failed to erase client data: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type AdminClientResponse struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role"`
+	Suspended     bool   `json:"suspended"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type AdminClientListResponse struct {
+	Clients []AdminClientResponse `json:"clients"`
+}
+
+type AdminWalletResponse struct {
+	ClientID string  `json:"client_id"`
+	Balance  float64 `json:"balance"`
+}
+
+type BetResponse struct {
+	ID           string    `json:"id"`
+	Amount       float64   `json:"amount"`
+	Choice       string    `json:"choice"`
+	Number       int       `json:"number"`
+	Result       string    `json:"result"`
+	BalanceAfter float64   `json:"balance_after"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type AdminBetListResponse struct {
+	Bets []BetResponse `json:"bets"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type AdjustBalanceRequest struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+type AdjustmentResponse struct {
+	ID          string     `json:"id"`
+	ClientID    string     `json:"client_id"`
+	Amount      float64    `json:"amount"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	RequestedBy string     `json:"requested_by"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+type AdjustmentListResponse struct {
+	Adjustments []AdjustmentResponse `json:"adjustments"`
+}
@@ -4,3 +4,8 @@ type PlaceBetResponse struct {
 	Result string `json:"result"`
 	Number int    `json:"number"`
 }
+
+type PlaceBetRequest struct {
+	Amount float64 `json:"amount"`
+	Choice string  `json:"choice"`
+}
@@ -5,8 +5,32 @@ type ClientLoginRequest struct {
 	Password string `json:"password"`
 }
 
+type ReauthRequest struct {
+	Password string `json:"password"`
+	// obrigatório apenas para clientes com 2FA
+	Code string `json:"code,omitempty"`
+}
+
+type ClientLoginResponse struct {
+	Token       string `json:"token"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+}
+
 type CreateClientRequest struct {
 	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email"`
+}
+
+type CloseAccountRequest struct {
 	Password string `json:"password"`
 }
 
@@ -17,3 +41,15 @@ type CreateClientResponse struct {
 type GetBalanceResponse struct {
 	Balance float64 `json:"balance"`
 }
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ValidationErrorResponse struct {
+	Error   string       `json:"error"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields"`
+}
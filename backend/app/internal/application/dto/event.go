@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type BalanceUpdatedEvent struct {
+	Balance float64 `json:"balance"`
+}
+
+type BetSettledEvent struct {
+	Bet BetResponse `json:"bet"`
+}
+
+type MatchEndedEvent struct {
+	Balance float64 `json:"balance"`
+}
+
+type SessionRevokedEvent struct {
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type APIKeyRevokedEvent struct {
+	KeyID     string    `json:"key_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
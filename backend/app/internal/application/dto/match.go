@@ -0,0 +1,5 @@
+package dto
+
+type MatchResponse struct {
+	MatchID string `json:"match_id"`
+}
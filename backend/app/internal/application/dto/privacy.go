@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type EraseAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type ExportProfile struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type ExportSession struct {
+	IP           string    `json:"ip"`
+	UserAgent    string    `json:"user_agent"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+type ExportTransaction struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+	ReferenceID  string    `json:"reference_id"`
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type DataExportResponse struct {
+	GeneratedAt  time.Time           `json:"generated_at"`
+	Profile      ExportProfile       `json:"profile"`
+	Wallet       GetBalanceResponse  `json:"wallet"`
+	Sessions     []ExportSession     `json:"sessions"`
+	Bets         []BetResponse       `json:"bets"`
+	Transactions []ExportTransaction `json:"transactions"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+type MFAEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/domain/entity"
+	"game/api/internal/infra/database"
+)
+
+type Adjustments struct {
+	db *database.Postgres
+}
+
+func NewAdjustments(db *database.Postgres) *Adjustments {
+	return &Adjustments{
+		db: db,
+	}
+}
+
+func (a *Adjustments) Add(ctx context.Context, adj entity.BalanceAdjustment) error {
+	return a.db.InsertAdjustment(ctx, database.AdjustmentData{
+		GUID:        adj.ID.String(),
+		ClientID:    adj.ClientID.String(),
+		Amount:      adj.Amount,
+		Reason:      adj.Reason,
+		RequestedBy: adj.RequestedBy.String(),
+	})
+}
+
+func (a *Adjustments) Get(ctx context.Context, id uuid.UUID) (entity.BalanceAdjustment, error) {
+	aData, err := a.db.FindAdjustment(ctx, id.String())
+	if err != nil {
+		return entity.BalanceAdjustment{}, err
+	}
+	return loadAdjustment(aData)
+}
+
+func (a *Adjustments) ListByStatus(ctx context.Context, status entity.AdjustmentStatus, limit, offset int) ([]entity.BalanceAdjustment, error) {
+	aData, err := a.db.FindAdjustmentsByStatus(ctx, string(status), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := make([]entity.BalanceAdjustment, 0, len(aData))
+	for _, d := range aData {
+		adj, err := loadAdjustment(d)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, adj)
+	}
+	return adjustments, nil
+}
+
+// Apply efetiva um ajuste pendente e retorna o saldo resultante da carteira.
+// O cache da carteira não é atualizado aqui.
+func (a *Adjustments) Apply(ctx context.Context, id, decidedBy uuid.UUID) (entity.BalanceAdjustment, float64, error) {
+	aData, balance, err := a.db.ApplyAdjustment(ctx, id.String(), decidedBy.String())
+	if err != nil {
+		return entity.BalanceAdjustment{}, 0, err
+	}
+	adj, err := loadAdjustment(aData)
+	return adj, balance, err
+}
+
+func (a *Adjustments) Reject(ctx context.Context, id, decidedBy uuid.UUID) error {
+	return a.db.RejectAdjustment(ctx, id.String(), decidedBy.String())
+}
+
+func loadAdjustment(d database.AdjustmentData) (adj entity.BalanceAdjustment, err error) {
+	adj = entity.BalanceAdjustment{
+		Amount: d.Amount,
+		Reason: d.Reason,
+		Status: entity.AdjustmentStatus(d.Status),
+	}
+	if adj.ID, err = uuid.Parse(d.GUID); err != nil {
+		return
+	}
+	if adj.ClientID, err = uuid.Parse(d.ClientID); err != nil {
+		return
+	}
+	if adj.RequestedBy, err = uuid.Parse(d.RequestedBy); err != nil {
+		return
+	}
+	if adj.DecidedBy, err = parseOptionalUUID(d.DecidedBy); err != nil {
+		return
+	}
+	adj.CreatedAt, _ = time.Parse(time.RFC3339Nano, d.CreatedAt)
+	if d.DecidedAt != nil {
+		if decidedAt, err := time.Parse(time.RFC3339Nano, *d.DecidedAt); err == nil {
+			adj.DecidedAt = &decidedAt
+		}
+	}
+	return
+}
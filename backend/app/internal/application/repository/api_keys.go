@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"game/api/internal/domain/entity"
+	"game/api/internal/infra/database"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	apiKeyKeyPrefix     = "api_key:"
+	apiKeyRateKeyPrefix = "api_key:rate:"
+	apiKeyCacheTTL      = 10 * time.Minute
+)
+
+type APIKeys struct {
+	cache *database.Redis
+	db    *database.Postgres
+}
+
+func NewAPIKeys(
+	cache *database.Redis,
+	db *database.Postgres,
+) *APIKeys {
+	return &APIKeys{
+		cache: cache,
+		db:    db,
+	}
+}
+
+func (a *APIKeys) Add(ctx context.Context, key entity.APIKey, hash string) error {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return a.db.InsertAPIKey(ctx, database.APIKeyData{
+		GUID:      key.ID.String(),
+		ClientID:  key.ClientID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		RateLimit: key.RateLimit,
+	})
+}
+
+// GetByHash é chamado a cada requisição autenticada por chave, por isso usa o
+// cache. Revoke remove a entrada, então chaves revogadas não sobrevivem ao
+// cache.
+func (a *APIKeys) GetByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	key := apiKeyKeyPrefix + hash
+	var kData database.APIKeyData
+
+	err := a.cache.Get(ctx, key, &kData)
+	if err == redis.Nil {
+		kData, err = a.db.FindAPIKeyByHash(ctx, hash)
+		if err != nil {
+			return entity.APIKey{}, err
+		}
+		if err := a.cache.SetWithTTL(ctx, key, kData, apiKeyCacheTTL); err != nil {
+			logger.Errorf("Failed to set API key to cache: %v", err)
+		}
+	} else if err != nil {
+		return entity.APIKey{}, err
+	}
+	return loadAPIKey(kData)
+}
+
+func (a *APIKeys) ListByClient(ctx context.Context, clientID uuid.UUID) ([]entity.APIKey, error) {
+	kData, err := a.db.FindAPIKeysByClientID(ctx, clientID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]entity.APIKey, 0, len(kData))
+	for _, d := range kData {
+		key, err := loadAPIKey(d)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (a *APIKeys) Revoke(ctx context.Context, clientID, keyID uuid.UUID) error {
+	hash, err := a.db.RevokeAPIKey(ctx, clientID.String(), keyID.String())
+	if err != nil {
+		return err
+	}
+	return a.cache.Delete(ctx, apiKeyKeyPrefix+hash)
+}
+
+// RevokeAll revoga todas as chaves do cliente e retorna seus IDs
+func (a *APIKeys) RevokeAll(ctx context.Context, clientID uuid.UUID) ([]string, error) {
+	kData, err := a.db.RevokeAPIKeysByClientID(ctx, clientID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(kData))
+	for _, d := range kData {
+		if err := a.cache.Delete(ctx, apiKeyKeyPrefix+d.KeyHash); err != nil {
+			return nil, err
+		}
+		ids = append(ids, d.GUID)
+	}
+	return ids, nil
+}
+
+// Hit conta uma requisição da chave na janela corrente e retorna o total e o
+// tempo restante da janela.
+func (a *APIKeys) Hit(ctx context.Context, keyID uuid.UUID, window time.Duration) (int64, time.Duration, error) {
+	key := apiKeyRateKeyPrefix + keyID.String()
+	count, err := a.cache.Increment(ctx, key, window)
+	if err != nil {
+		return 0, 0, err
+	}
+	ttl, err := a.cache.TTL(ctx, key)
+	return count, ttl, err
+}
+
+func loadAPIKey(d database.APIKeyData) (key entity.APIKey, err error) {
+	key = entity.APIKey{
+		Name:      d.Name,
+		Prefix:    d.Prefix,
+		RateLimit: d.RateLimit,
+	}
+	if key.ID, err = uuid.Parse(d.GUID); err != nil {
+		return
+	}
+	if key.ClientID, err = uuid.Parse(d.ClientID); err != nil {
+		return
+	}
+	for _, s := range d.Scopes {
+		if scope, ok := entity.ParseAPIKeyScope(s); ok {
+			key.Scopes = append(key.Scopes, scope)
+		}
+	}
+	key.CreatedAt, _ = time.Parse(time.RFC3339Nano, d.CreatedAt)
+	return
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/domain/entity"
+	"game/api/internal/infra/database"
+)
+
+type Bets struct {
+	db *database.Postgres
+}
+
+func NewBets(db *database.Postgres) *Bets {
+	return &Bets{
+		db: db,
+	}
+}
+
+func (b *Bets) ListByClient(ctx context.Context, clientID uuid.UUID, limit, offset int) (bets []entity.Bet, err error) {
+	bData, err := b.db.FindBetsByClientID(ctx, clientID.String(), limit, offset)
+	if err != nil {
+		return
+	}
+
+	bets = make([]entity.Bet, 0, len(bData))
+	for _, d := range bData {
+		bet := entity.Bet{
+			Amount:       d.Amount,
+			Choice:       d.Choice,
+			Number:       d.Number,
+			Result:       d.Result,
+			BalanceAfter: d.BalanceAfter,
+		}
+		if bet.ID, err = uuid.Parse(d.GUID); err != nil {
+			return nil, err
+		}
+		if bet.ClientID, err = uuid.Parse(d.ClientID); err != nil {
+			return nil, err
+		}
+		bet.CreatedAt, _ = time.Parse(time.RFC3339Nano, d.CreatedAt)
+		bets = append(bets, bet)
+	}
+	return
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -37,6 +38,9 @@ func (c *Clients) Add(client entity.Client) (err error) {
 		Username: client.GetUsername(),
 		Password: client.GetPassword(),
 	}
+	if email := client.GetEmail(); email != "" {
+		cData.Email = &email
+	}
 	err = c.db.InsertClient(cData)
 	if err != nil {
 		logger.Errorf("Failed to insert client: %v", err)
@@ -68,7 +72,7 @@ func (c *Clients) getFromCacheOrDB(ctx context.Context, key string, fetchFromDB
 }
 
 func (c *Clients) GetByUsername(ctx context.Context, username string) (client entity.Client, err error) {
-	key := clientKeyPrefix + username
+	key := clientKeyPrefix + strings.ToLower(username)
 	var cData database.ClientData
 
 	cData, err = c.getFromCacheOrDB(ctx, key, func() (database.ClientData, error) {
@@ -101,3 +105,132 @@ func (c *Clients) ClearCache(ctx context.Context, id uuid.UUID) (err error) {
 	key := clientKeyPrefix + id.String()
 	return c.cache.Delete(ctx, key)
 }
+
+// clearClientCache remove as duas entradas do cliente (por id e por username)
+func (c *Clients) clearClientCache(ctx context.Context, client entity.Client) error {
+	err := c.cache.Delete(ctx, clientKeyPrefix+client.GetID().String())
+	if err != nil {
+		return err
+	}
+	return c.cache.Delete(ctx, clientKeyPrefix+strings.ToLower(client.GetUsername()))
+}
+
+func (c *Clients) UpdatePassword(ctx context.Context, client entity.Client) (err error) {
+	err = c.db.UpdateClientPassword(ctx, client.GetID().String(), client.GetPassword())
+	if err != nil {
+		logger.Errorf("Failed to update client password: %v", err)
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) UpdateEmail(ctx context.Context, client entity.Client, email string) (err error) {
+	err = c.db.UpdateClientEmail(ctx, client.GetID().String(), email)
+	if err != nil {
+		logger.Errorf("Failed to update client email: %v", err)
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) MarkEmailVerified(ctx context.Context, client entity.Client, email string) (err error) {
+	err = c.db.MarkClientEmailVerified(ctx, client.GetID().String(), email)
+	if err != nil {
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) SetTOTP(ctx context.Context, client entity.Client, secret string, enabled bool) (err error) {
+	var s *string
+	if secret != "" {
+		s = &secret
+	}
+	err = c.db.UpdateClientTOTP(ctx, client.GetID().String(), s, enabled)
+	if err != nil {
+		logger.Errorf("Failed to update client TOTP: %v", err)
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) Search(ctx context.Context, term string, limit, offset int) (clients []entity.Client, err error) {
+	cData, err := c.db.SearchClients(ctx, term, limit, offset)
+	if err != nil {
+		return
+	}
+
+	clients = make([]entity.Client, 0, len(cData))
+	for _, d := range cData {
+		client, err := entity.LoadClient(d)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return
+}
+
+func (c *Clients) UpdateRole(ctx context.Context, client entity.Client, role entity.Role) (err error) {
+	err = c.db.UpdateClientRole(ctx, client.GetID().String(), string(role))
+	if err != nil {
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) Suspend(ctx context.Context, client entity.Client) (err error) {
+	err = c.db.SuspendClient(ctx, client.GetID().String())
+	if err != nil {
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) Unsuspend(ctx context.Context, client entity.Client) (err error) {
+	err = c.db.UnsuspendClient(ctx, client.GetID().String())
+	if err != nil {
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+func (c *Clients) Close(ctx context.Context, client entity.Client) (err error) {
+	err = c.db.CloseClient(ctx, client.GetID().String())
+	if err != nil {
+		return
+	}
+	return c.clearClientCache(ctx, client)
+}
+
+// GetIncludingClosed também encontra contas encerradas e, como
+// GetClosedByUsername, não usa o cache.
+func (c *Clients) GetIncludingClosed(ctx context.Context, id uuid.UUID) (client entity.Client, err error) {
+	cData, err := c.db.FindClientByIDIncludingClosed(ctx, id.String())
+	if err != nil {
+		return
+	}
+	return entity.LoadClient(cData)
+}
+
+// GetClosedByUsername não usa o cache: contas encerradas nunca são cacheadas.
+func (c *Clients) GetClosedByUsername(ctx context.Context, username string) (client entity.Client, err error) {
+	cData, err := c.db.FindClosedClientByUsername(ctx, username)
+	if err != nil {
+		return
+	}
+	return entity.LoadClient(cData)
+}
+
+// Erase anonimiza o cliente e remove as entradas de cache do id e do
+// username anterior.
+func (c *Clients) Erase(ctx context.Context, id uuid.UUID, username, password string) error {
+	previous, err := c.db.EraseClient(ctx, id.String(), username, password)
+	if err != nil {
+		return err
+	}
+	if err := c.cache.Delete(ctx, clientKeyPrefix+id.String()); err != nil {
+		return err
+	}
+	return c.cache.Delete(ctx, clientKeyPrefix+strings.ToLower(previous))
+}
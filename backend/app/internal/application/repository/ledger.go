@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/domain/entity"
+	"game/api/internal/infra/database"
+)
+
+type Ledger struct {
+	db *database.Postgres
+}
+
+func NewLedger(db *database.Postgres) *Ledger {
+	return &Ledger{
+		db: db,
+	}
+}
+
+func (l *Ledger) ListByClient(ctx context.Context, clientID uuid.UUID, limit, offset int) (entries []entity.LedgerEntry, err error) {
+	lData, err := l.db.FindLedgerEntriesByClientID(ctx, clientID.String(), limit, offset)
+	if err != nil {
+		return
+	}
+
+	entries = make([]entity.LedgerEntry, 0, len(lData))
+	for _, d := range lData {
+		entry := entity.LedgerEntry{
+			Type:         entity.LedgerEntryType(d.EntryType),
+			Amount:       d.Amount,
+			BalanceAfter: d.BalanceAfter,
+		}
+		if entry.ID, err = uuid.Parse(d.GUID); err != nil {
+			return nil, err
+		}
+		if entry.ClientID, err = uuid.Parse(d.ClientID); err != nil {
+			return nil, err
+		}
+		if entry.ReferenceID, err = uuid.Parse(d.ReferenceID); err != nil {
+			return nil, err
+		}
+		if d.Reason != nil {
+			entry.Reason = *d.Reason
+		}
+		if entry.CreatedBy, err = parseOptionalUUID(d.CreatedBy); err != nil {
+			return nil, err
+		}
+		if entry.ApprovedBy, err = parseOptionalUUID(d.ApprovedBy); err != nil {
+			return nil, err
+		}
+		entry.CreatedAt, _ = time.Parse(time.RFC3339Nano, d.CreatedAt)
+		entries = append(entries, entry)
+	}
+	return
+}
+
+func parseOptionalUUID(s *string) (*uuid.UUID, error) {
+	if s == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/domain/entity"
+	"game/api/internal/infra/database"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	loginFailKeyPrefix = "login:fail:"
+	loginLockKeyPrefix = "login:lock:"
+
+	LoginScopeUsername = "user"
+	LoginScopeIP       = "ip"
+)
+
+type LoginAttempts struct {
+	cache *database.Redis
+	db    *database.Postgres
+}
+
+func NewLoginAttempts(
+	cache *database.Redis,
+	db *database.Postgres,
+) *LoginAttempts {
+	return &LoginAttempts{
+		cache: cache,
+		db:    db,
+	}
+}
+
+func loginKey(prefix, scope, id string) string {
+	return prefix + scope + ":" + strings.ToLower(id)
+}
+
+func (l *LoginAttempts) LockedFor(ctx context.Context, scope, id string) (time.Duration, error) {
+	return l.cache.TTL(ctx, loginKey(loginLockKeyPrefix, scope, id))
+}
+
+func (l *LoginAttempts) AddFailure(ctx context.Context, scope, id string, window time.Duration) (int64, error) {
+	return l.cache.Increment(ctx, loginKey(loginFailKeyPrefix, scope, id), window)
+}
+
+func (l *LoginAttempts) Lock(ctx context.Context, scope, id string, duration time.Duration) error {
+	return l.cache.SetWithTTL(ctx, loginKey(loginLockKeyPrefix, scope, id), time.Now().Add(duration), duration)
+}
+
+func (l *LoginAttempts) Reset(ctx context.Context, scope, id string) error {
+	err := l.cache.Delete(ctx, loginKey(loginFailKeyPrefix, scope, id))
+	if err != nil {
+		return err
+	}
+	return l.cache.Delete(ctx, loginKey(loginLockKeyPrefix, scope, id))
+}
+
+func (l *LoginAttempts) Audit(ctx context.Context, attempt entity.LoginAttempt) (err error) {
+	aData := database.LoginAttemptData{
+		GUID:      uuid.New().String(),
+		Username:  attempt.Username,
+		IP:        attempt.IP,
+		UserAgent: attempt.UserAgent,
+		Reason:    attempt.Reason,
+	}
+	if attempt.ClientID != nil {
+		clientID := attempt.ClientID.String()
+		aData.ClientID = &clientID
+	}
+	err = l.db.InsertLoginAttempt(ctx, aData)
+	if err != nil {
+		logger.Errorf("Failed to audit login attempt: %v", err)
+	}
+	return
+}
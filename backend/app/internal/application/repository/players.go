@@ -34,53 +34,37 @@ func NewPlayers(
 	}
 }
 
-func (p *Players) getFromCacheOrDB(ctx context.Context, key string, fetchFromDB func() (database.PlayerData, error)) (pData database.PlayerData, err error) {
-	lockKey := "lock:" + key
-	err = p.cache.WithLock(ctx, lockKey, 5*time.Second, 3, 100*time.Millisecond, func() error {
-		err = p.cache.Get(ctx, key, &pData)
-		if err != nil && err == redis.Nil {
-			pData, err = fetchFromDB()
-			if err != nil {
-				return err
-			}
-
-			err = p.cache.Set(ctx, key, pData)
-			if err != nil {
-				logger.Errorf("Failed to set player to cache: %v", err)
-				return err
-			}
-		} else if err != nil {
-			return err
-		}
-		return nil
-	})
-	return
-}
-
-func (p *Players) Get(ctx context.Context, clientID uuid.UUID) (entity.Player, error) {
+// load lê o jogador do cache ou, na falta, do banco. Deve ser chamado sob o
+// lock do jogador.
+func (p *Players) load(ctx context.Context, clientID uuid.UUID) (entity.Player, error) {
 	key := playerKeyPrefix + clientID.String()
 	var pData database.PlayerData
 
-	pData, err := p.getFromCacheOrDB(ctx, key, func() (database.PlayerData, error) {
+	err := p.cache.Get(ctx, key, &pData)
+	if err != nil && err == redis.Nil {
 		client, err := p.repoClient.Get(ctx, clientID)
 		if err != nil {
 			logger.Errorf("Error getting client from repository: %v", err)
-			return database.PlayerData{}, err
+			return entity.Player{}, err
 		}
 
 		wallet, err := p.repoWallet.Get(ctx, clientID)
 		if err != nil {
 			logger.Errorf("Error getting wallet from repository: %v", err)
-			return database.PlayerData{}, err
+			return entity.Player{}, err
 		}
 
-		return database.PlayerData{
+		pData = database.PlayerData{
 			ClientID: client.GetID().String(),
 			Balance:  wallet.Balance,
 			InPlay:   false,
-		}, nil
-	})
-	if err != nil {
+		}
+		err = p.cache.Set(ctx, key, pData)
+		if err != nil {
+			logger.Errorf("Failed to set player to cache: %v", err)
+			return entity.Player{}, err
+		}
+	} else if err != nil {
 		return entity.Player{}, err
 	}
 
@@ -90,30 +74,74 @@ func (p *Players) Get(ctx context.Context, clientID uuid.UUID) (entity.Player, e
 		return entity.Player{}, err
 	}
 
-	return entity.Player{
+	player := entity.Player{
 		ClientID: clientUUID,
 		Balance:  pData.Balance,
 		InPlay:   pData.InPlay,
-	}, nil
+	}
+	if pData.MatchID != "" {
+		if player.MatchID, err = uuid.Parse(pData.MatchID); err != nil {
+			logger.Errorf("Failed to parse match ID: %v", err)
+			return entity.Player{}, err
+		}
+	}
+	return player, nil
 }
 
-func (p *Players) Set(ctx context.Context, player *entity.Player) error {
-	key := playerKeyPrefix + player.ClientID.String()
-	lockKey := "lock:" + key
+// store grava o jogador no cache. Deve ser chamado sob o lock do jogador.
+func (p *Players) store(ctx context.Context, player *entity.Player) error {
 	playerData := database.PlayerData{
 		ClientID: player.ClientID.String(),
 		Balance:  player.Balance,
 		InPlay:   player.InPlay,
 	}
+	if player.MatchID != uuid.Nil {
+		playerData.MatchID = player.MatchID.String()
+	}
 
-	return p.cache.WithLock(ctx, lockKey, 5*time.Second, 3, 100*time.Millisecond, func() error {
-		err := p.cache.Set(ctx, key, playerData)
-		if err != nil {
-			logger.Errorf("Failed to set player to cache: %v", err)
+	err := p.cache.Set(ctx, playerKeyPrefix+player.ClientID.String(), playerData)
+	if err != nil {
+		logger.Errorf("Failed to set player to cache: %v", err)
+		return err
+	}
+	return nil
+}
+
+// WithLock executa fn sob lock:player:<id>, o mesmo lock de Get, Set e
+// Update, para operações que alteram o saldo fora do cache do jogador
+func (p *Players) WithLock(ctx context.Context, clientID uuid.UUID, fn func() error) error {
+	lockKey := "lock:" + playerKeyPrefix + clientID.String()
+	return p.cache.WithLock(ctx, lockKey, 5*time.Second, 3, 100*time.Millisecond, fn)
+}
+
+func (p *Players) Get(ctx context.Context, clientID uuid.UUID) (player entity.Player, err error) {
+	err = p.WithLock(ctx, clientID, func() error {
+		player, err = p.load(ctx, clientID)
+		return err
+	})
+	return
+}
+
+func (p *Players) Set(ctx context.Context, player *entity.Player) error {
+	return p.WithLock(ctx, player.ClientID, func() error {
+		return p.store(ctx, player)
+	})
+}
+
+// Update lê o jogador, aplica fn e grava o resultado sem soltar o lock, para
+// que apostas e ajustes concorrentes não sobrescrevam o saldo um do outro.
+// Se fn retornar erro nada é gravado.
+func (p *Players) Update(ctx context.Context, clientID uuid.UUID, fn func(player *entity.Player) error) (player entity.Player, err error) {
+	err = p.WithLock(ctx, clientID, func() error {
+		if player, err = p.load(ctx, clientID); err != nil {
 			return err
 		}
-		return nil
+		if err := fn(&player); err != nil {
+			return err
+		}
+		return p.store(ctx, &player)
 	})
+	return
 }
 
 func (p *Players) EndGame(ctx context.Context, playerID uuid.UUID) error {
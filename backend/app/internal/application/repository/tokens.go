@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/database"
+)
+
+const (
+	passwordResetKeyPrefix     = "password_reset:"
+	emailVerificationKeyPrefix = "email_verification:"
+	// sufixos sob o prefixo; não colidem com os hashes hexadecimais
+	latestTokenKey = "client:"
+	cooldownKey    = "cooldown:"
+)
+
+type oneTimeToken struct {
+	ClientID string `json:"client_id"`
+	Subject  string `json:"subject,omitempty"`
+}
+
+// OneTimeTokens guarda tokens de uso único (redefinição de senha,
+// verificação de e-mail) associados a um cliente.
+type OneTimeTokens struct {
+	cache  *database.Redis
+	prefix string
+}
+
+func NewPasswordResets(cache *database.Redis) *OneTimeTokens {
+	return &OneTimeTokens{
+		cache:  cache,
+		prefix: passwordResetKeyPrefix,
+	}
+}
+
+func NewEmailVerifications(cache *database.Redis) *OneTimeTokens {
+	return &OneTimeTokens{
+		cache:  cache,
+		prefix: emailVerificationKeyPrefix,
+	}
+}
+
+// Save guarda apenas o hash do token; o token em si só existe na mensagem
+// enviada ao cliente. subject identifica o alvo do token (ex.: o e-mail a
+// ser verificado) e pode ser vazio. O token anterior do cliente deixa de
+// valer, de forma que apenas a mensagem mais recente funcione.
+func (t *OneTimeTokens) Save(ctx context.Context, tokenHash string, clientID uuid.UUID, subject string, ttl time.Duration) error {
+	latest := t.prefix + latestTokenKey + clientID.String()
+
+	var previous string
+	err := t.cache.Get(ctx, latest, &previous)
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	if previous != "" && previous != tokenHash {
+		err = t.cache.Delete(ctx, t.prefix+previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = t.cache.SetWithTTL(ctx, t.prefix+tokenHash, oneTimeToken{
+		ClientID: clientID.String(),
+		Subject:  subject,
+	}, ttl)
+	if err != nil {
+		return err
+	}
+	return t.cache.SetWithTTL(ctx, latest, tokenHash, ttl)
+}
+
+// Cooldown reserva key por ttl e retorna false se ela já estava reservada
+func (t *OneTimeTokens) Cooldown(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return t.cache.SetNX(ctx, t.prefix+cooldownKey+key, true, ttl)
+}
+
+// Consume remove o token ao lê-lo, garantindo uso único
+func (t *OneTimeTokens) Consume(ctx context.Context, tokenHash string) (clientID uuid.UUID, subject string, err error) {
+	var data oneTimeToken
+	err = t.cache.Take(ctx, t.prefix+tokenHash, &data)
+	if err == redis.Nil {
+		err = errs.ErrNotFound
+		return
+	}
+	if err != nil {
+		return
+	}
+	clientID, err = uuid.Parse(data.ClientID)
+	return clientID, data.Subject, err
+}
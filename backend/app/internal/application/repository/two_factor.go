@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"game/api/internal/infra/database"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	totpUsedKeyPrefix = "totp:used:"
+)
+
+type TwoFactor struct {
+	cache *database.Redis
+	db    *database.Postgres
+}
+
+func NewTwoFactor(
+	cache *database.Redis,
+	db *database.Postgres,
+) *TwoFactor {
+	return &TwoFactor{
+		cache: cache,
+		db:    db,
+	}
+}
+
+func (t *TwoFactor) ReplaceRecoveryCodes(ctx context.Context, clientID uuid.UUID, hashes []string) (err error) {
+	err = t.db.ReplaceRecoveryCodes(ctx, clientID.String(), hashes)
+	if err != nil {
+		logger.Errorf("Failed to replace recovery codes: %v", err)
+	}
+	return
+}
+
+func (t *TwoFactor) DeleteRecoveryCodes(ctx context.Context, clientID uuid.UUID) error {
+	return t.db.DeleteRecoveryCodes(ctx, clientID.String())
+}
+
+func (t *TwoFactor) UseRecoveryCode(ctx context.Context, clientID uuid.UUID, hash string) (bool, error) {
+	return t.db.UseRecoveryCode(ctx, clientID.String(), hash)
+}
+
+// MarkStepUsed registra o passo TOTP consumido e retorna false se o mesmo
+// código já foi usado, evitando replay dentro da janela de validade.
+func (t *TwoFactor) MarkStepUsed(ctx context.Context, clientID uuid.UUID, step int64, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%s:%d", totpUsedKeyPrefix, clientID.String(), step)
+	return t.cache.SetNX(ctx, key, step, ttl)
+}
@@ -16,6 +16,8 @@ const (
 	walletKeyPrefix = "wallet:"
 )
 
+// Wallets não publica eventos: as gravações acontecem sob o lock do jogador
+// e quem chama publica BalanceUpdated depois de soltá-lo.
 type Wallets struct {
 	cache *database.Redis
 	db    *database.Postgres
@@ -83,27 +85,47 @@ func (w *Wallets) Get(ctx context.Context, clientID uuid.UUID) (wallet entity.Wa
 	return
 }
 
-func (w *Wallets) Update(ctx context.Context, wallet entity.Wallet) (err error) {
+// SettleBet grava o saldo após a aposta junto com a aposta e o lançamento
+// no ledger; se a transação falhar, nada é gravado e o cache não muda.
+func (w *Wallets) SettleBet(ctx context.Context, wallet entity.Wallet, bet entity.Bet) error {
 	key := walletKeyPrefix + wallet.ClientID.String()
 	lockKey := "lock:" + key
 	walletData := database.WalletData{
 		Balance:  wallet.Balance,
 		ClientID: wallet.ClientID.String(),
 	}
+	delta := -bet.Amount
+	if bet.Result == "win" {
+		delta = bet.Amount
+	}
 
 	return w.cache.WithLock(ctx, lockKey, 5*time.Second, 3, 100*time.Millisecond, func() error {
-		err = w.db.UpdateWallet(ctx, walletData)
+		err := w.db.SettleBet(ctx, walletData, database.BetData{
+			GUID:         bet.ID.String(),
+			ClientID:     bet.ClientID.String(),
+			Amount:       bet.Amount,
+			Choice:       bet.Choice,
+			Number:       bet.Number,
+			Result:       bet.Result,
+			BalanceAfter: bet.BalanceAfter,
+		}, database.LedgerEntryData{
+			GUID:         uuid.New().String(),
+			ClientID:     bet.ClientID.String(),
+			EntryType:    string(entity.LedgerEntryBet),
+			Amount:       delta,
+			BalanceAfter: bet.BalanceAfter,
+			ReferenceID:  bet.ID.String(),
+		})
 		if err != nil {
-			logger.Errorf("Failed to update wallet in database: %v", err)
+			logger.Errorf("Failed to settle bet in database: %v", err)
 			return err
 		}
 
-		err = w.cache.Set(ctx, key, walletData)
-		if err != nil {
+		// a aposta já está gravada: uma falha no cache não pode desfazê-la
+		if err := w.cache.Set(ctx, key, walletData); err != nil {
 			logger.Errorf("Failed to set wallet to cache: %v", err)
-			return err
+			w.cache.Delete(ctx, key)
 		}
-
 		return nil
 	})
 }
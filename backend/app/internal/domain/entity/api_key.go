@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyScope string
+
+const (
+	ScopeWalletRead APIKeyScope = "wallet:read"
+	ScopeBetsPlace  APIKeyScope = "bets:place"
+)
+
+func ParseAPIKeyScope(s string) (APIKeyScope, bool) {
+	switch scope := APIKeyScope(s); scope {
+	case ScopeWalletRead, ScopeBetsPlace:
+		return scope, true
+	}
+	return "", false
+}
+
+type APIKey struct {
+	ID        uuid.UUID
+	ClientID  uuid.UUID
+	Name      string
+	Prefix    string
+	Scopes    []APIKeyScope
+	RateLimit int
+	CreatedAt time.Time
+}
+
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Bet struct {
+	ID           uuid.UUID
+	ClientID     uuid.UUID
+	Amount       float64
+	Choice       string
+	Number       int
+	Result       string
+	BalanceAfter float64
+	CreatedAt    time.Time
+}
@@ -8,12 +8,22 @@ import (
 )
 
 type Client struct {
-	id       uuid.UUID
-	username string
-	password string
+	id          uuid.UUID
+	username    string
+	password    string
+	totpSecret  string
+	totpEnabled bool
+
+	email         string
+	emailVerified bool
+
+	role      Role
+	suspended bool
+	closed    bool
+	createdAt string
 }
 
-func NewClient(username, password string, balance float64) (Client, error) {
+func NewClient(username, email, password string, balance float64) (Client, error) {
 	hashedPassword, err := HashPassword(password)
 	if err != nil {
 		return Client{}, err
@@ -23,6 +33,8 @@ func NewClient(username, password string, balance float64) (Client, error) {
 		id:       uuid.New(),
 		username: username,
 		password: hashedPassword,
+		email:    email,
+		role:     RolePlayer,
 	}, nil
 }
 
@@ -34,15 +46,64 @@ func (c *Client) GetUsername() string {
 	return c.username
 }
 
+func (c *Client) GetEmail() string {
+	return c.email
+}
+
+func (c *Client) EmailVerified() bool {
+	return c.emailVerified
+}
+
+func (c *Client) GetRole() Role {
+	return c.role
+}
+
+func (c *Client) Suspended() bool {
+	return c.suspended
+}
+
+func (c *Client) Closed() bool {
+	return c.closed
+}
+
+func (c *Client) GetCreatedAt() string {
+	return c.createdAt
+}
+
 func (c *Client) GetPassword() string {
 	return c.password
 }
 
+func (c *Client) SetPassword(password string) error {
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	c.password = hashedPassword
+	return nil
+}
+
+func (c *Client) GetTOTPSecret() string {
+	return c.totpSecret
+}
+
+func (c *Client) TOTPEnabled() bool {
+	return c.totpEnabled
+}
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
 }
 
+// hash usado quando o usuário não existe, para que o tempo de resposta do
+// login não revele quais usernames estão cadastrados
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+func CheckDummyPasswordHash(password string) {
+	_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+}
+
 func (c *Client) CheckPasswordHash(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(c.password), []byte(password))
 	return err == nil
@@ -55,5 +116,20 @@ func LoadClient(cData database.ClientData) (c Client, err error) {
 	}
 	c.username = cData.Username
 	c.password = cData.Password
+	if cData.TOTPSecret != nil {
+		c.totpSecret = *cData.TOTPSecret
+	}
+	c.totpEnabled = cData.TOTPEnabledAt != nil
+	if cData.Email != nil {
+		c.email = *cData.Email
+	}
+	c.emailVerified = cData.EmailVerifiedAt != nil
+	c.role = RolePlayer
+	if role, ok := ParseRole(cData.Role); ok {
+		c.role = role
+	}
+	c.suspended = cData.SuspendedAt != nil
+	c.closed = cData.DeletedAt != nil
+	c.createdAt = cData.CreatedAt
 	return
 }
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LedgerEntryType string
+
+const (
+	LedgerEntryBet        LedgerEntryType = "bet"
+	LedgerEntryAdjustment LedgerEntryType = "adjustment"
+)
+
+type AdjustmentStatus string
+
+const (
+	AdjustmentPending  AdjustmentStatus = "pending"
+	AdjustmentApplied  AdjustmentStatus = "applied"
+	AdjustmentRejected AdjustmentStatus = "rejected"
+)
+
+type BalanceAdjustment struct {
+	ID          uuid.UUID
+	ClientID    uuid.UUID
+	Amount      float64
+	Reason      string
+	Status      AdjustmentStatus
+	RequestedBy uuid.UUID
+	DecidedBy   *uuid.UUID
+	CreatedAt   time.Time
+	DecidedAt   *time.Time
+}
+
+// CanBeApprovedBy aplica a aprovação em duas pessoas: quem pediu o ajuste não
+// pode aprová-lo
+func (a BalanceAdjustment) CanBeApprovedBy(adminID uuid.UUID) bool {
+	return adminID != uuid.Nil && adminID != a.RequestedBy
+}
+
+type LedgerEntry struct {
+	ID           uuid.UUID
+	ClientID     uuid.UUID
+	Type         LedgerEntryType
+	Amount       float64
+	BalanceAfter float64
+	ReferenceID  uuid.UUID
+	Reason       string
+	CreatedBy    *uuid.UUID
+	ApprovedBy   *uuid.UUID
+	CreatedAt    time.Time
+}
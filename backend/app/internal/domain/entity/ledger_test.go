@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCanBeApprovedBy(t *testing.T) {
+	requester := uuid.New()
+	other := uuid.New()
+	adj := BalanceAdjustment{ID: uuid.New(), RequestedBy: requester, Status: AdjustmentPending}
+
+	tests := []struct {
+		name    string
+		adminID uuid.UUID
+		want    bool
+	}{
+		{"another admin", other, true},
+		{"the requester", requester, false},
+		{"no admin", uuid.Nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adj.CanBeApprovedBy(tt.adminID); got != tt.want {
+				t.Errorf("CanBeApprovedBy = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "github.com/google/uuid"
+
+const (
+	LoginFailureUnknownUser     = "unknown_user"
+	LoginFailureInvalidPassword = "invalid_password"
+	LoginFailureInvalidMFACode  = "invalid_mfa_code"
+	LoginFailureLocked          = "locked"
+)
+
+type LoginAttempt struct {
+	Username  string
+	ClientID  *uuid.UUID
+	IP        string
+	UserAgent string
+	Reason    string
+}
@@ -6,14 +6,22 @@ type Player struct {
 	ClientID uuid.UUID
 	Balance  float64
 	InPlay   bool
+	// partida em andamento; zero fora de partida
+	MatchID uuid.UUID
 }
 
-func (p *Player) PlayOn() {
+func (p *Player) PlayOn(matchID uuid.UUID) {
 	p.InPlay = true
+	p.MatchID = matchID
 }
 
 func (p *Player) PlayOff() {
 	p.InPlay = false
+	p.MatchID = uuid.Nil
+}
+
+func (p *Player) InMatch(matchID uuid.UUID) bool {
+	return p.InPlay && p.MatchID == matchID
 }
 
 func (p *Player) GetBalance() float64 {
@@ -0,0 +1,64 @@
+package entity
+
+type Role string
+
+const (
+	RolePlayer  Role = "player"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermClientsRead    Permission = "clients:read"
+	PermClientsSuspend Permission = "clients:suspend"
+	PermClientsClose   Permission = "clients:close"
+	PermClientsExport  Permission = "clients:export"
+	PermClientsErase   Permission = "clients:erase"
+	PermWalletsRead    Permission = "wallets:read"
+	PermWalletsAdjust  Permission = "wallets:adjust"
+	PermBetsRead       Permission = "bets:read"
+	PermRolesManage    Permission = "roles:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleSupport: {
+		PermClientsRead,
+		PermWalletsRead,
+		PermBetsRead,
+	},
+	RoleAdmin: {
+		PermClientsRead,
+		PermClientsSuspend,
+		PermClientsClose,
+		PermClientsExport,
+		PermClientsErase,
+		PermWalletsRead,
+		PermWalletsAdjust,
+		PermBetsRead,
+		PermRolesManage,
+	},
+}
+
+func ParseRole(s string) (Role, bool) {
+	switch r := Role(s); r {
+	case RolePlayer, RoleSupport, RoleAdmin:
+		return r, true
+	}
+	return "", false
+}
+
+func (r Role) Can(p Permission) bool {
+	for _, perm := range rolePermissions[r] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaff indica se o papel tem acesso ao grupo /admin
+func (r Role) IsStaff() bool {
+	return len(rolePermissions[r]) > 0
+}
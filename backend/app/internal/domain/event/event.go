@@ -0,0 +1,115 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+type Type string
+
+const (
+	// Payload: entity.Wallet
+	BalanceUpdated Type = "balance_updated"
+	// Payload: entity.Bet
+	BetSettled Type = "bet_settled"
+	// Payload: entity.Player com o saldo final
+	MatchEnded Type = "match_ended"
+	// Payload: SessionRevokedPayload
+	SessionRevoked Type = "session_revoked"
+	// Payload: APIKeyRevokedPayload
+	APIKeyRevoked Type = "api_key_revoked"
+)
+
+type Event struct {
+	Type       Type
+	ClientID   uuid.UUID
+	Payload    interface{}
+	OccurredAt time.Time
+}
+
+type SessionRevokedPayload struct {
+	// tokens das sessões revogadas; nunca são enviados ao cliente
+	Tokens []string
+}
+
+type APIKeyRevokedPayload struct {
+	KeyID string
+}
+
+type Handler func(ctx context.Context, e Event)
+
+// Bus distribui eventos de domínio para os assinantes de forma síncrona, na
+// goroutine de quem publica. Handlers devem ser rápidos e, se fizerem I/O,
+// não devem depender do ctx recebido: ele é o da requisição de quem publicou
+// e pode ser cancelado logo em seguida.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+	all      []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[Type][]Handler),
+	}
+}
+
+// Subscribe registra o handler para os tipos informados; sem tipos, recebe
+// todos os eventos.
+func (b *Bus) Subscribe(h Handler, types ...Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(types) == 0 {
+		b.all = append(b.all, h)
+		return
+	}
+	for _, t := range types {
+		b.handlers[t] = append(b.handlers[t], h)
+	}
+}
+
+// Publish pode ser chamado em um Bus nil, o que facilita componentes que não
+// precisam de eventos.
+func (b *Bus) Publish(ctx context.Context, t Type, clientID uuid.UUID, payload interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.all)+len(b.handlers[t]))
+	handlers = append(handlers, b.all...)
+	handlers = append(handlers, b.handlers[t]...)
+	b.mu.RUnlock()
+
+	e := Event{
+		Type:       t,
+		ClientID:   clientID,
+		Payload:    payload,
+		OccurredAt: time.Now(),
+	}
+	for _, h := range handlers {
+		b.dispatch(ctx, h, e)
+	}
+}
+
+// dispatch isola o publicador de falhas do handler: o evento é consequência
+// de uma operação que já foi concluída.
+func (b *Bus) dispatch(ctx context.Context, h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.WithFields(logrus.Fields{
+				"event":     e.Type,
+				"client_id": e.ClientID,
+				"panic":     r,
+			}).Error("Event handler panicked")
+		}
+	}()
+	h(ctx, e)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/event"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AccountService controla o estado da conta (suspensa, encerrada). Tanto o
+// painel admin quanto o próprio cliente passam por aqui.
+type AccountService struct {
+	clientsRepo    *repository.Clients
+	playerRepo     *repository.Players
+	apiKeysRepo    *repository.APIKeys
+	sessionManager *session.Manager
+	events         *event.Bus
+}
+
+func NewAccountService(
+	clientsRepo *repository.Clients,
+	playerRepo *repository.Players,
+	apiKeysRepo *repository.APIKeys,
+	sessionManager *session.Manager,
+	events *event.Bus,
+) *AccountService {
+	return &AccountService{
+		clientsRepo:    clientsRepo,
+		playerRepo:     playerRepo,
+		apiKeysRepo:    apiKeysRepo,
+		sessionManager: sessionManager,
+		events:         events,
+	}
+}
+
+func (s *AccountService) Suspend(ctx context.Context, actorID, clientID uuid.UUID) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if client.Suspended() {
+		return nil
+	}
+
+	if err := s.clientsRepo.Suspend(ctx, client); err != nil {
+		return err
+	}
+	if err := s.signOut(ctx, clientID); err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"actor_id":  actorID,
+		"client_id": clientID,
+	}).Info("Client suspended")
+	return nil
+}
+
+func (s *AccountService) Unsuspend(ctx context.Context, actorID, clientID uuid.UUID) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if !client.Suspended() {
+		return nil
+	}
+
+	if err := s.clientsRepo.Unsuspend(ctx, client); err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"actor_id":  actorID,
+		"client_id": clientID,
+	}).Info("Client unsuspended")
+	return nil
+}
+
+// Close encerra a conta (soft delete). O registro e a carteira são mantidos,
+// mas o cliente deixa de ser encontrado e não consegue mais entrar.
+func (s *AccountService) Close(ctx context.Context, actorID, clientID uuid.UUID) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.clientsRepo.Close(ctx, client); err != nil {
+		return err
+	}
+	if _, err := s.apiKeysRepo.RevokeAll(ctx, clientID); err != nil {
+		return err
+	}
+	if err := s.signOut(ctx, clientID); err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"actor_id":  actorID,
+		"client_id": clientID,
+	}).Info("Client account closed")
+	return nil
+}
+
+// CloseOwn encerra a conta do próprio cliente mediante a senha atual.
+func (s *AccountService) CloseOwn(ctx context.Context, clientID uuid.UUID, password string) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if !client.CheckPasswordHash(password) {
+		return errs.ErrInvalidPassword
+	}
+	return s.Close(ctx, clientID, clientID)
+}
+
+// signOut revoga todas as sessões, fecha todas as conexões do cliente e
+// encerra a partida em andamento. As chaves de API não são revogadas: a
+// autenticação as recusa enquanto a conta estiver suspensa e elas voltam a
+// valer com Unsuspend; Close as revoga.
+func (s *AccountService) signOut(ctx context.Context, clientID uuid.UUID) error {
+	if _, err := s.sessionManager.RevokeAll(ctx, clientID.String(), ""); err != nil {
+		return err
+	}
+	// sem tokens, o evento fecha todas as conexões, inclusive as abertas
+	// com chave de API
+	s.events.Publish(ctx, event.SessionRevoked, clientID, event.SessionRevokedPayload{})
+	if err := s.playerRepo.EndGame(ctx, clientID); err != nil {
+		logger.Errorf("Failed to end active match: %v", err)
+		return err
+	}
+	return nil
+}
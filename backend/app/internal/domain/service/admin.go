@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/entity"
+	"game/api/internal/domain/event"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FieldRole   = "role"
+	FieldAmount = "amount"
+	FieldReason = "reason"
+
+	codeInvalidRole   = "invalid_role"
+	codeInvalidAmount = "invalid_amount"
+
+	maxReasonLength = 500
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type AdminService struct {
+	clientsRepo     *repository.Clients
+	walletRepo      *repository.Wallets
+	playerRepo      *repository.Players
+	betsRepo        *repository.Bets
+	adjustmentsRepo *repository.Adjustments
+	accountService  *AccountService
+	privacyService  *PrivacyService
+	sessionManager  *session.Manager
+	events          *event.Bus
+	// ajustes com valor absoluto acima do limite exigem aprovação de um
+	// segundo admin
+	approvalThreshold float64
+}
+
+func NewAdminService(
+	clientsRepo *repository.Clients,
+	walletRepo *repository.Wallets,
+	playerRepo *repository.Players,
+	betsRepo *repository.Bets,
+	adjustmentsRepo *repository.Adjustments,
+	accountService *AccountService,
+	privacyService *PrivacyService,
+	sessionManager *session.Manager,
+	events *event.Bus,
+	approvalThreshold float64,
+) *AdminService {
+	return &AdminService{
+		clientsRepo:       clientsRepo,
+		walletRepo:        walletRepo,
+		playerRepo:        playerRepo,
+		betsRepo:          betsRepo,
+		adjustmentsRepo:   adjustmentsRepo,
+		accountService:    accountService,
+		privacyService:    privacyService,
+		sessionManager:    sessionManager,
+		events:            events,
+		approvalThreshold: approvalThreshold,
+	}
+}
+
+func page(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func (s *AdminService) SearchClients(ctx context.Context, term string, limit, offset int) ([]entity.Client, error) {
+	limit, offset = page(limit, offset)
+	return s.clientsRepo.Search(ctx, term, limit, offset)
+}
+
+func (s *AdminService) GetClient(ctx context.Context, clientID uuid.UUID) (entity.Client, error) {
+	return s.clientsRepo.Get(ctx, clientID)
+}
+
+func (s *AdminService) GetWallet(ctx context.Context, clientID uuid.UUID) (entity.Wallet, error) {
+	if _, err := s.clientsRepo.Get(ctx, clientID); err != nil {
+		return entity.Wallet{}, err
+	}
+	return s.walletRepo.Get(ctx, clientID)
+}
+
+func (s *AdminService) ListBets(ctx context.Context, clientID uuid.UUID, limit, offset int) ([]entity.Bet, error) {
+	if _, err := s.clientsRepo.Get(ctx, clientID); err != nil {
+		return nil, err
+	}
+	limit, offset = page(limit, offset)
+	return s.betsRepo.ListByClient(ctx, clientID, limit, offset)
+}
+
+func (s *AdminService) Suspend(ctx context.Context, adminID, clientID uuid.UUID) error {
+	if adminID == clientID {
+		return errs.ErrForbidden
+	}
+	return s.accountService.Suspend(ctx, adminID, clientID)
+}
+
+func (s *AdminService) Unsuspend(ctx context.Context, adminID, clientID uuid.UUID) error {
+	return s.accountService.Unsuspend(ctx, adminID, clientID)
+}
+
+func (s *AdminService) Close(ctx context.Context, adminID, clientID uuid.UUID) error {
+	if adminID == clientID {
+		return errs.ErrForbidden
+	}
+	return s.accountService.Close(ctx, adminID, clientID)
+}
+
+func (s *AdminService) ExportClient(ctx context.Context, clientID uuid.UUID) (DataExport, error) {
+	return s.privacyService.Export(ctx, clientID)
+}
+
+func (s *AdminService) EraseClient(ctx context.Context, adminID, clientID uuid.UUID) error {
+	if adminID == clientID {
+		return errs.ErrForbidden
+	}
+	return s.privacyService.Erase(ctx, adminID, clientID)
+}
+
+func (s *AdminService) SetRole(ctx context.Context, adminID, clientID uuid.UUID, roleName string) error {
+	role, ok := entity.ParseRole(roleName)
+	if !ok {
+		var v errs.ValidationError
+		v.Add(FieldRole, codeInvalidRole, "must be one of player, support, admin")
+		return v.Err()
+	}
+
+	// um admin não altera o próprio papel (nem se rebaixa por engano)
+	if adminID == clientID {
+		return errs.ErrForbidden
+	}
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	previous := client.GetRole()
+	if previous == role {
+		return nil
+	}
+
+	if err := s.clientsRepo.UpdateRole(ctx, client, role); err != nil {
+		return err
+	}
+	// o papel vai no token; sessões antigas carregariam o papel anterior
+	if _, err := s.sessionManager.RevokeAll(ctx, clientID.String(), ""); err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"admin_id":  adminID,
+		"client_id": clientID,
+		"from":      previous,
+		"to":        role,
+	}).Info("Client role changed")
+	return nil
+}
+
+// AdjustBalance registra um ajuste manual na carteira do cliente. Ajustes até
+// o limite são aplicados imediatamente; acima dele ficam pendentes até que
+// outro admin aprove.
+func (s *AdminService) AdjustBalance(ctx context.Context, adminID, clientID uuid.UUID, amount float64, reason string) (entity.BalanceAdjustment, error) {
+	reason = strings.TrimSpace(reason)
+
+	var v errs.ValidationError
+	if amount == 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		v.Add(FieldAmount, codeInvalidAmount, "must be a non-zero number")
+	}
+	if reason == "" {
+		v.Add(FieldReason, codeRequired, "is required")
+	} else if len(reason) > maxReasonLength {
+		v.Add(FieldReason, codeTooLong, fmt.Sprintf("must be at most %d characters", maxReasonLength))
+	}
+	if err := v.Err(); err != nil {
+		return entity.BalanceAdjustment{}, err
+	}
+
+	if _, err := s.clientsRepo.Get(ctx, clientID); err != nil {
+		return entity.BalanceAdjustment{}, err
+	}
+
+	adj := entity.BalanceAdjustment{
+		ID:          uuid.New(),
+		ClientID:    clientID,
+		Amount:      amount,
+		Reason:      reason,
+		Status:      entity.AdjustmentPending,
+		RequestedBy: adminID,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.adjustmentsRepo.Add(ctx, adj); err != nil {
+		return entity.BalanceAdjustment{}, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"admin_id":      adminID,
+		"client_id":     clientID,
+		"adjustment_id": adj.ID,
+		"amount":        amount,
+	}).Info("Balance adjustment requested")
+
+	if math.Abs(amount) > s.approvalThreshold {
+		return adj, nil
+	}
+	return s.apply(ctx, adminID, clientID, adj.ID)
+}
+
+func (s *AdminService) ApproveAdjustment(ctx context.Context, adminID, adjustmentID uuid.UUID) (entity.BalanceAdjustment, error) {
+	adj, err := s.adjustmentsRepo.Get(ctx, adjustmentID)
+	if err != nil {
+		return entity.BalanceAdjustment{}, err
+	}
+	if !adj.CanBeApprovedBy(adminID) {
+		return entity.BalanceAdjustment{}, errs.ErrForbidden
+	}
+	return s.apply(ctx, adminID, adj.ClientID, adjustmentID)
+}
+
+func (s *AdminService) RejectAdjustment(ctx context.Context, adminID, adjustmentID uuid.UUID) error {
+	if err := s.adjustmentsRepo.Reject(ctx, adjustmentID, adminID); err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"admin_id":      adminID,
+		"adjustment_id": adjustmentID,
+	}).Info("Balance adjustment rejected")
+	return nil
+}
+
+func (s *AdminService) ListAdjustments(ctx context.Context, status string, limit, offset int) ([]entity.BalanceAdjustment, error) {
+	if status == "" {
+		status = string(entity.AdjustmentPending)
+	}
+	limit, offset = page(limit, offset)
+	return s.adjustmentsRepo.ListByStatus(ctx, entity.AdjustmentStatus(status), limit, offset)
+}
+
+// apply altera a carteira sob o lock do jogador: uma aposta concorrente
+// gravaria o saldo anterior ao ajuste por cima dele. Apenas o saldo em cache
+// muda; a partida em andamento continua.
+func (s *AdminService) apply(ctx context.Context, adminID, clientID, adjustmentID uuid.UUID) (entity.BalanceAdjustment, error) {
+	var (
+		adj     entity.BalanceAdjustment
+		balance float64
+	)
+	_, err := s.playerRepo.Update(ctx, clientID, func(player *entity.Player) (err error) {
+		adj, balance, err = s.adjustmentsRepo.Apply(ctx, adjustmentID, adminID)
+		if err != nil {
+			return err
+		}
+		player.Balance = balance
+
+		if err := s.walletRepo.ClearCache(ctx, adj.ClientID); err != nil {
+			logger.Errorf("Failed to clear wallet cache: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.BalanceAdjustment{}, err
+	}
+	// fora do lock: a entrega dos eventos acessa o Redis e pode demorar
+	s.events.Publish(ctx, event.BalanceUpdated, adj.ClientID, entity.Wallet{ClientID: adj.ClientID, Balance: balance})
+
+	logger.WithFields(logrus.Fields{
+		"admin_id":      adminID,
+		"client_id":     adj.ClientID,
+		"adjustment_id": adj.ID,
+		"amount":        adj.Amount,
+		"balance":       balance,
+	}).Info("Balance adjustment applied")
+	return adj, nil
+}
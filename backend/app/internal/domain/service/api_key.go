@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/entity"
+	"game/api/internal/domain/event"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FieldName      = "name"
+	FieldScopes    = "scopes"
+	FieldRateLimit = "rate_limit"
+
+	codeInvalidScope = "invalid_scope"
+	codeOutOfRange   = "out_of_range"
+	codeLimitReached = "limit_reached"
+
+	apiKeyPrefix       = "gk_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 6
+	maxAPIKeyNameLen   = 60
+	apiKeyRateWindow   = time.Minute
+)
+
+type APIKeyPolicy struct {
+	MaxKeysPerClient int
+	// requisições por minuto
+	DefaultRateLimit int
+	MaxRateLimit     int
+}
+
+func DefaultAPIKeyPolicy() APIKeyPolicy {
+	return APIKeyPolicy{
+		MaxKeysPerClient: 10,
+		DefaultRateLimit: 60,
+		MaxRateLimit:     600,
+	}
+}
+
+// APIKeyService emite e valida chaves de API para bots e integrações. Só o
+// hash da chave é persistido; a chave completa é exibida uma única vez.
+type APIKeyService struct {
+	apiKeysRepo *repository.APIKeys
+	clientsRepo *repository.Clients
+	events      *event.Bus
+	policy      APIKeyPolicy
+}
+
+func NewAPIKeyService(
+	apiKeysRepo *repository.APIKeys,
+	clientsRepo *repository.Clients,
+	events *event.Bus,
+	policy APIKeyPolicy,
+) *APIKeyService {
+	return &APIKeyService{
+		apiKeysRepo: apiKeysRepo,
+		clientsRepo: clientsRepo,
+		events:      events,
+		policy:      policy,
+	}
+}
+
+func (s *APIKeyService) Create(ctx context.Context, clientID uuid.UUID, name string, scopes []string, rateLimit int) (string, entity.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if rateLimit == 0 {
+		rateLimit = s.policy.DefaultRateLimit
+	}
+
+	var v errs.ValidationError
+	if name == "" {
+		v.Add(FieldName, codeRequired, "is required")
+	} else if len(name) > maxAPIKeyNameLen {
+		v.Add(FieldName, codeTooLong, fmt.Sprintf("must be at most %d characters", maxAPIKeyNameLen))
+	}
+	parsed := make([]entity.APIKeyScope, 0, len(scopes))
+	for _, sc := range scopes {
+		scope, ok := entity.ParseAPIKeyScope(sc)
+		if !ok {
+			v.Add(FieldScopes, codeInvalidScope, fmt.Sprintf("unknown scope %q", sc))
+			continue
+		}
+		parsed = append(parsed, scope)
+	}
+	if len(scopes) == 0 {
+		v.Add(FieldScopes, codeRequired, "at least one scope is required")
+	}
+	if rateLimit < 1 || rateLimit > s.policy.MaxRateLimit {
+		v.Add(FieldRateLimit, codeOutOfRange, fmt.Sprintf("must be between 1 and %d", s.policy.MaxRateLimit))
+	}
+	if err := v.Err(); err != nil {
+		return "", entity.APIKey{}, err
+	}
+
+	existing, err := s.apiKeysRepo.ListByClient(ctx, clientID)
+	if err != nil {
+		return "", entity.APIKey{}, err
+	}
+	if len(existing) >= s.policy.MaxKeysPerClient {
+		v.Add(FieldName, codeLimitReached, fmt.Sprintf("at most %d active keys are allowed", s.policy.MaxKeysPerClient))
+		return "", entity.APIKey{}, v.Err()
+	}
+
+	token, _, err := newOneTimeToken()
+	if err != nil {
+		return "", entity.APIKey{}, err
+	}
+	secret := apiKeyPrefix + token
+
+	key := entity.APIKey{
+		ID:        uuid.New(),
+		ClientID:  clientID,
+		Name:      name,
+		Prefix:    secret[:apiKeyPrefixLength],
+		Scopes:    parsed,
+		RateLimit: rateLimit,
+		CreatedAt: time.Now(),
+	}
+	if err := s.apiKeysRepo.Add(ctx, key, hashToken(secret)); err != nil {
+		return "", entity.APIKey{}, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+		"key_id":    key.ID,
+		"scopes":    scopes,
+	}).Info("API key created")
+	return secret, key, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, clientID uuid.UUID) ([]entity.APIKey, error) {
+	return s.apiKeysRepo.ListByClient(ctx, clientID)
+}
+
+// Revoke também encerra as conexões abertas com a chave (WebSocket e SSE),
+// que só foram autenticadas na abertura
+func (s *APIKeyService) Revoke(ctx context.Context, clientID, keyID uuid.UUID) error {
+	if err := s.apiKeysRepo.Revoke(ctx, clientID, keyID); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, event.APIKeyRevoked, clientID, event.APIKeyRevokedPayload{KeyID: keyID.String()})
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+		"key_id":    keyID,
+	}).Info("API key revoked")
+	return nil
+}
+
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, secret string) (session.APIKeyPrincipal, error) {
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return session.APIKeyPrincipal{}, errs.ErrInvalidAPIKey
+	}
+	key, err := s.apiKeysRepo.GetByHash(ctx, hashToken(secret))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return session.APIKeyPrincipal{}, errs.ErrInvalidAPIKey
+		}
+		return session.APIKeyPrincipal{}, err
+	}
+
+	// contas encerradas não são encontradas; suspensas são recusadas
+	client, err := s.clientsRepo.Get(ctx, key.ClientID)
+	if err != nil {
+		return session.APIKeyPrincipal{}, err
+	}
+	if client.Suspended() {
+		return session.APIKeyPrincipal{}, errs.ErrAccountSuspended
+	}
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, sc := range key.Scopes {
+		scopes = append(scopes, string(sc))
+	}
+	return session.APIKeyPrincipal{
+		KeyID:     key.ID.String(),
+		ClientID:  key.ClientID.String(),
+		Scopes:    scopes,
+		RateLimit: key.RateLimit,
+	}, nil
+}
+
+func (s *APIKeyService) ConsumeAPIKey(ctx context.Context, principal session.APIKeyPrincipal) error {
+	keyID, err := uuid.Parse(principal.KeyID)
+	if err != nil {
+		return err
+	}
+	count, ttl, err := s.apiKeysRepo.Hit(ctx, keyID, apiKeyRateWindow)
+	if err != nil {
+		return err
+	}
+	if count > int64(principal.RateLimit) {
+		return &errs.RetryError{Err: errs.ErrRateLimited, RetryAfter: ttl}
+	}
+	return nil
+}
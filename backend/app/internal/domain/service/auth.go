@@ -2,51 +2,372 @@ package service
 
 import (
 	"context"
+	"errors"
+	"math"
+	"time"
 
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/entity"
 	"game/api/internal/errs"
 	"game/api/internal/infra/logger"
 	"game/api/internal/infra/session"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
+type LoginPolicy struct {
+	MaxUsernameFailures int64
+	MaxIPFailures       int64
+	Window              time.Duration
+	BaseLockout         time.Duration
+	MaxLockout          time.Duration
+}
+
+func DefaultLoginPolicy() LoginPolicy {
+	return LoginPolicy{
+		MaxUsernameFailures: 5,
+		MaxIPFailures:       20,
+		Window:              15 * time.Minute,
+		BaseLockout:         30 * time.Second,
+		MaxLockout:          time.Hour,
+	}
+}
+
+// lockout calcula o bloqueio exponencial a partir do número de falhas
+func (p LoginPolicy) lockout(failures, max int64) time.Duration {
+	if failures < max {
+		return 0
+	}
+	d := time.Duration(float64(p.BaseLockout) * math.Pow(2, float64(failures-max)))
+	if d <= 0 || d > p.MaxLockout {
+		return p.MaxLockout
+	}
+	return d
+}
+
+type LoginResult struct {
+	Token       string
+	MFARequired bool
+}
+
 type AuthService struct {
-	clientService  *ClientService
-	sessionManager *session.Manager
+	clientService    *ClientService
+	twoFactorService *TwoFactorService
+	sessionManager   *session.Manager
+	loginAttempts    *repository.LoginAttempts
+	loginPolicy      LoginPolicy
 }
 
-func NewAuthService(clientService *ClientService, sessionManager *session.Manager) *AuthService {
+func NewAuthService(
+	clientService *ClientService,
+	twoFactorService *TwoFactorService,
+	sessionManager *session.Manager,
+	loginAttempts *repository.LoginAttempts,
+	loginPolicy LoginPolicy,
+) *AuthService {
 	return &AuthService{
-		clientService:  clientService,
-		sessionManager: sessionManager,
+		clientService:    clientService,
+		twoFactorService: twoFactorService,
+		sessionManager:   sessionManager,
+		loginAttempts:    loginAttempts,
+		loginPolicy:      loginPolicy,
 	}
 }
 
-func (s *AuthService) Login(ctx context.Context, username, password, ip, userAgent string) (string, error) {
+func (s *AuthService) Login(ctx context.Context, username, password, ip, userAgent string) (LoginResult, error) {
+	attempt := entity.LoginAttempt{
+		Username:  username,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+
+	retryAfter, err := s.lockedFor(ctx, username, ip)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if retryAfter > 0 {
+		attempt.Reason = entity.LoginFailureLocked
+		s.loginAttempts.Audit(ctx, attempt)
+		return LoginResult{}, &errs.RetryError{Err: errs.ErrTooManyAttempts, RetryAfter: retryAfter}
+	}
+
 	client, err := s.clientService.GetByUsername(ctx, username)
+	if errors.Is(err, errs.ErrNotFound) {
+		// a conta encerrada só é revelada a quem acerta a senha
+		client, err = s.clientService.GetClosedByUsername(ctx, username)
+	}
 	if err != nil {
-		return "", err
+		if !errors.Is(err, errs.ErrNotFound) {
+			return LoginResult{}, err
+		}
+		entity.CheckDummyPasswordHash(password)
+		attempt.Reason = entity.LoginFailureUnknownUser
+		return LoginResult{}, s.loginFailed(ctx, attempt)
 	}
 
 	if !client.CheckPasswordHash(password) {
-		return "", errs.ErrInvalidPassword
+		clientID := client.GetID()
+		attempt.ClientID = &clientID
+		attempt.Reason = entity.LoginFailureInvalidPassword
+		return LoginResult{}, s.loginFailed(ctx, attempt)
+	}
+
+	err = s.loginAttempts.Reset(ctx, repository.LoginScopeUsername, username)
+	if err != nil {
+		logger.Errorf("Failed to reset login attempts: %v", err)
+	}
+
+	if client.Closed() {
+		return LoginResult{}, errs.ErrAccountClosed
+	}
+	if client.Suspended() {
+		return LoginResult{}, errs.ErrAccountSuspended
 	}
 
 	sess := session.Session{
 		ClientID:  client.GetID().String(),
+		Role:      string(client.GetRole()),
 		IP:        ip,
 		UserAgent: userAgent,
 	}
 
+	if client.TOTPEnabled() {
+		token, err := s.sessionManager.CreatePending(ctx, sess)
+		if err != nil {
+			logger.Errorf("Failed to create pending session: %v", err)
+			return LoginResult{}, err
+		}
+		return LoginResult{Token: token, MFARequired: true}, nil
+	}
+
 	token, err := s.sessionManager.Create(ctx, sess)
 	if err != nil {
 		logger.Errorf("Failed to create session: %v", err)
+		return LoginResult{}, err
+	}
+
+	return LoginResult{Token: token}, nil
+}
+
+// VerifyMFA conclui o login de um cliente com 2FA trocando o token parcial
+// por uma sessão completa.
+func (s *AuthService) VerifyMFA(ctx context.Context, partialToken, code string) (string, error) {
+	sess, err := s.sessionManager.Get(ctx, partialToken)
+	if err != nil {
+		return "", err
+	}
+	if sess == nil || !sess.MFAPending {
+		return "", errs.ErrNotFound
+	}
+
+	clientID, err := uuid.Parse(sess.ClientID)
+	if err != nil {
+		return "", err
+	}
+	client, err := s.clientService.Get(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.verifyMFA(ctx, client, code, sess.IP, sess.UserAgent); err != nil {
+		return "", err
+	}
+
+	err = s.sessionManager.Delete(ctx, partialToken)
+	if err != nil {
 		return "", err
 	}
 
+	now := time.Now()
+	token, err := s.sessionManager.Create(ctx, session.Session{
+		ClientID:      sess.ClientID,
+		Role:          sess.Role,
+		IP:            sess.IP,
+		UserAgent:     sess.UserAgent,
+		MFAVerifiedAt: &now,
+	})
+	if err != nil {
+		logger.Errorf("Failed to create session: %v", err)
+		return "", err
+	}
 	return token, nil
 }
 
+// ConfirmMFA revalida o 2FA na sessão atual, exigido por operações sensíveis
+// como saques.
+func (s *AuthService) ConfirmMFA(ctx context.Context, clientID uuid.UUID, token, code, ip, userAgent string) error {
+	client, err := s.clientService.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyMFA(ctx, client, code, ip, userAgent); err != nil {
+		return err
+	}
+	return s.sessionManager.MarkMFAVerified(ctx, token)
+}
+
+func (s *AuthService) DisableMFA(ctx context.Context, clientID uuid.UUID, code, ip, userAgent string) error {
+	client, err := s.clientService.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyMFA(ctx, client, code, ip, userAgent); err != nil {
+		return err
+	}
+	return s.twoFactorService.Disable(ctx, client)
+}
+
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, clientID uuid.UUID, code, ip, userAgent string) ([]string, error) {
+	client, err := s.clientService.Get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyMFA(ctx, client, code, ip, userAgent); err != nil {
+		return nil, err
+	}
+	return s.twoFactorService.RegenerateRecoveryCodes(ctx, clientID)
+}
+
+// verifyMFA confere o código 2FA com o mesmo bloqueio do login: falhas
+// contam para o username e o IP, impedindo a força bruta com uma sessão já
+// aberta.
+func (s *AuthService) verifyMFA(ctx context.Context, client entity.Client, code, ip, userAgent string) error {
+	clientID := client.GetID()
+	attempt := entity.LoginAttempt{
+		Username:  client.GetUsername(),
+		ClientID:  &clientID,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+	retryAfter, err := s.lockedFor(ctx, attempt.Username, ip)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		attempt.Reason = entity.LoginFailureLocked
+		s.loginAttempts.Audit(ctx, attempt)
+		return &errs.RetryError{Err: errs.ErrTooManyAttempts, RetryAfter: retryAfter}
+	}
+
+	err = s.twoFactorService.Verify(ctx, client, code)
+	if err != nil {
+		if !errors.Is(err, errs.ErrInvalidMFACode) {
+			return err
+		}
+		attempt.Reason = entity.LoginFailureInvalidMFACode
+		s.loginFailed(ctx, attempt)
+		return errs.ErrInvalidMFACode
+	}
+
+	err = s.loginAttempts.Reset(ctx, repository.LoginScopeUsername, attempt.Username)
+	if err != nil {
+		logger.Errorf("Failed to reset login attempts: %v", err)
+	}
+	return nil
+}
+
+// Reauthenticate confirma a senha (e o 2FA, se habilitado) de uma sessão
+// cuja origem mudou e a vincula à nova origem. Falhas contam para o bloqueio
+// do login.
+func (s *AuthService) Reauthenticate(ctx context.Context, clientID uuid.UUID, token, password, code, ip, userAgent string) error {
+	client, err := s.clientService.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	attempt := entity.LoginAttempt{
+		Username:  client.GetUsername(),
+		ClientID:  &clientID,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+	retryAfter, err := s.lockedFor(ctx, attempt.Username, ip)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		attempt.Reason = entity.LoginFailureLocked
+		s.loginAttempts.Audit(ctx, attempt)
+		return &errs.RetryError{Err: errs.ErrTooManyAttempts, RetryAfter: retryAfter}
+	}
+
+	if !client.CheckPasswordHash(password) {
+		attempt.Reason = entity.LoginFailureInvalidPassword
+		return s.loginFailed(ctx, attempt)
+	}
+	if client.TOTPEnabled() {
+		err = s.twoFactorService.Verify(ctx, client, code)
+		if err != nil {
+			if !errors.Is(err, errs.ErrInvalidMFACode) {
+				return err
+			}
+			attempt.Reason = entity.LoginFailureInvalidMFACode
+			s.loginFailed(ctx, attempt)
+			return errs.ErrInvalidMFACode
+		}
+	}
+
+	err = s.loginAttempts.Reset(ctx, repository.LoginScopeUsername, attempt.Username)
+	if err != nil {
+		logger.Errorf("Failed to reset login attempts: %v", err)
+	}
+	return s.sessionManager.Rebind(ctx, token, ip, userAgent)
+}
+
+func (s *AuthService) lockedFor(ctx context.Context, username, ip string) (time.Duration, error) {
+	userLock, err := s.loginAttempts.LockedFor(ctx, repository.LoginScopeUsername, username)
+	if err != nil {
+		return 0, err
+	}
+	ipLock, err := s.loginAttempts.LockedFor(ctx, repository.LoginScopeIP, ip)
+	if err != nil {
+		return 0, err
+	}
+	if ipLock > userLock {
+		return ipLock, nil
+	}
+	return userLock, nil
+}
+
+// loginFailed registra a falha, aplica o bloqueio quando necessário e sempre
+// retorna o mesmo erro para não revelar se o username existe.
+func (s *AuthService) loginFailed(ctx context.Context, attempt entity.LoginAttempt) error {
+	s.loginAttempts.Audit(ctx, attempt)
+
+	userFailures, err := s.loginAttempts.AddFailure(ctx, repository.LoginScopeUsername, attempt.Username, s.loginPolicy.Window)
+	if err != nil {
+		return err
+	}
+	ipFailures, err := s.loginAttempts.AddFailure(ctx, repository.LoginScopeIP, attempt.IP, s.loginPolicy.Window)
+	if err != nil {
+		return err
+	}
+
+	if d := s.loginPolicy.lockout(userFailures, s.loginPolicy.MaxUsernameFailures); d > 0 {
+		if err := s.loginAttempts.Lock(ctx, repository.LoginScopeUsername, attempt.Username, d); err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{
+			"username": attempt.Username,
+			"failures": userFailures,
+			"lockout":  d,
+		}).Warn("Username temporarily locked")
+	}
+	if d := s.loginPolicy.lockout(ipFailures, s.loginPolicy.MaxIPFailures); d > 0 {
+		if err := s.loginAttempts.Lock(ctx, repository.LoginScopeIP, attempt.IP, d); err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{
+			"ip":       attempt.IP,
+			"failures": ipFailures,
+			"lockout":  d,
+		}).Warn("IP temporarily locked")
+	}
+
+	return errs.ErrInvalidCredentials
+}
+
 func (s *AuthService) Logout(ctx context.Context, clientID uuid.UUID, token string) error {
 	err := s.clientService.RefreshWallet(ctx, clientID)
 	if err != nil {
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginPolicyLockout(t *testing.T) {
+	p := DefaultLoginPolicy()
+
+	tests := []struct {
+		name     string
+		failures int64
+		max      int64
+		want     time.Duration
+	}{
+		{"no failures", 0, p.MaxUsernameFailures, 0},
+		{"below username threshold", p.MaxUsernameFailures - 1, p.MaxUsernameFailures, 0},
+		{"at username threshold", p.MaxUsernameFailures, p.MaxUsernameFailures, p.BaseLockout},
+		{"one over doubles", p.MaxUsernameFailures + 1, p.MaxUsernameFailures, 2 * p.BaseLockout},
+		{"three over", p.MaxUsernameFailures + 3, p.MaxUsernameFailures, 8 * p.BaseLockout},
+		{"last step below the cap", p.MaxUsernameFailures + 6, p.MaxUsernameFailures, 64 * p.BaseLockout},
+		{"capped at max lockout", p.MaxUsernameFailures + 7, p.MaxUsernameFailures, p.MaxLockout},
+		// o expoente estoura a duração: continua no teto, não volta a zero
+		{"overflow stays capped", p.MaxUsernameFailures + 1000, p.MaxUsernameFailures, p.MaxLockout},
+		{"below IP threshold", p.MaxIPFailures - 1, p.MaxIPFailures, 0},
+		{"at IP threshold", p.MaxIPFailures, p.MaxIPFailures, p.BaseLockout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.lockout(tt.failures, tt.max); got != tt.want {
+				t.Errorf("lockout(%d, %d) = %v, want %v", tt.failures, tt.max, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"strings"
 
 	"game/api/internal/application/repository"
 	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
 	"game/api/internal/infra/logger"
 
 	"github.com/google/uuid"
@@ -12,19 +14,39 @@ import (
 )
 
 type ClientService struct {
-	clientsRepo *repository.Clients
-	walletRepo  *repository.Wallets
+	clientsRepo  *repository.Clients
+	walletRepo   *repository.Wallets
+	emailService *EmailService
+	credentials  CredentialsPolicy
 }
 
-func NewClientService(clientsRepo *repository.Clients, walletRepo *repository.Wallets) *ClientService {
+func NewClientService(
+	clientsRepo *repository.Clients,
+	walletRepo *repository.Wallets,
+	emailService *EmailService,
+	credentials CredentialsPolicy,
+) *ClientService {
 	return &ClientService{
-		clientsRepo: clientsRepo,
-		walletRepo:  walletRepo,
+		clientsRepo:  clientsRepo,
+		walletRepo:   walletRepo,
+		emailService: emailService,
+		credentials:  credentials,
 	}
 }
 
-func (s *ClientService) Create(ctx context.Context, username, password string) (clientID uuid.UUID, err error) {
-	client, err := entity.NewClient(username, password, 0)
+func (s *ClientService) Create(ctx context.Context, username, email, password string) (clientID uuid.UUID, err error) {
+	username = strings.TrimSpace(username)
+	email = strings.TrimSpace(email)
+
+	var v errs.ValidationError
+	s.credentials.ValidateUsername(&v, username)
+	ValidateEmail(&v, email)
+	s.credentials.ValidatePassword(&v, FieldPassword, password, username)
+	if err = v.Err(); err != nil {
+		return
+	}
+
+	client, err := entity.NewClient(username, email, password, 0)
 	if err != nil {
 		return
 	}
@@ -42,6 +64,11 @@ func (s *ClientService) Create(ctx context.Context, username, password string) (
 		return
 	}
 
+	// falha no envio não desfaz o cadastro; o cliente pode pedir reenvio
+	if err := s.emailService.SendVerification(ctx, client); err != nil {
+		logger.Errorf("Failed to send verification email: %v", err)
+	}
+
 	return client.GetID(), nil
 }
 
@@ -55,6 +82,20 @@ func (s *ClientService) GetByUsername(ctx context.Context, username string) (cli
 	return client, nil
 }
 
+func (s *ClientService) GetClosedByUsername(ctx context.Context, username string) (entity.Client, error) {
+	return s.clientsRepo.GetClosedByUsername(ctx, username)
+}
+
+func (s *ClientService) Get(ctx context.Context, clientID uuid.UUID) (client entity.Client, err error) {
+	client, err = s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		logger.Errorf("Failed to get client: %v", err)
+		return
+	}
+
+	return client, nil
+}
+
 func (s *ClientService) GetBalance(ctx context.Context, clientID uuid.UUID) (balance float64, err error) {
 	err = s.RefreshWallet(ctx, clientID)
 	if err != nil {
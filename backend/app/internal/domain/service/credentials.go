@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	FieldUsername    = "username"
+	FieldPassword    = "password"
+	FieldNewPassword = "new_password"
+
+	codeRequired = "required"
+	codeTooShort = "too_short"
+	codeTooLong  = "too_long"
+	codeCharset  = "invalid_characters"
+	codeReserved = "reserved"
+	codeBreached = "breached"
+	codeSame     = "same_as_username"
+	CodeTaken    = "taken"
+
+	// limite do bcrypt
+	maxPasswordBytes = 72
+)
+
+type CredentialsPolicy struct {
+	UsernameMinLength int
+	UsernameMaxLength int
+	UsernamePattern   *regexp.Regexp
+	ReservedUsernames map[string]struct{}
+	PasswordMinLength int
+	BreachedPasswords map[string]struct{}
+}
+
+func DefaultCredentialsPolicy() CredentialsPolicy {
+	return CredentialsPolicy{
+		UsernameMinLength: 3,
+		UsernameMaxLength: 30,
+		UsernamePattern:   regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`),
+		ReservedUsernames: NewNameSet("admin", "administrator", "root", "support", "system", "moderator", "null"),
+		PasswordMinLength: 8,
+		BreachedPasswords: map[string]struct{}{},
+	}
+}
+
+func NewNameSet(names ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			set[name] = struct{}{}
+		}
+	}
+	return set
+}
+
+// LoadBreachedPasswords lê uma senha vazada por linha; linhas vazias e
+// iniciadas por # são ignoradas.
+func LoadBreachedPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	set := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Loaded %d breached passwords from %s", len(set), path)
+	return set, nil
+}
+
+func (p CredentialsPolicy) ValidateUsername(v *errs.ValidationError, username string) {
+	length := utf8.RuneCountInString(username)
+	switch {
+	case length == 0:
+		v.Add(FieldUsername, codeRequired, "is required")
+		return
+	case length < p.UsernameMinLength:
+		v.Add(FieldUsername, codeTooShort, fmt.Sprintf("must be at least %d characters", p.UsernameMinLength))
+	case length > p.UsernameMaxLength:
+		v.Add(FieldUsername, codeTooLong, fmt.Sprintf("must be at most %d characters", p.UsernameMaxLength))
+	}
+	if p.UsernamePattern != nil && !p.UsernamePattern.MatchString(username) {
+		v.Add(FieldUsername, codeCharset, "contains invalid characters")
+	}
+	if _, ok := p.ReservedUsernames[strings.ToLower(username)]; ok {
+		v.Add(FieldUsername, codeReserved, "is reserved")
+	}
+}
+
+func (p CredentialsPolicy) ValidatePassword(v *errs.ValidationError, field, password, username string) {
+	switch {
+	case password == "":
+		v.Add(field, codeRequired, "is required")
+		return
+	case utf8.RuneCountInString(password) < p.PasswordMinLength:
+		v.Add(field, codeTooShort, fmt.Sprintf("must be at least %d characters", p.PasswordMinLength))
+	case len(password) > maxPasswordBytes:
+		v.Add(field, codeTooLong, fmt.Sprintf("must be at most %d bytes", maxPasswordBytes))
+	}
+	if username != "" && strings.EqualFold(password, username) {
+		v.Add(field, codeSame, "must not be the same as the username")
+		return
+	}
+	if _, ok := p.BreachedPasswords[strings.ToLower(password)]; ok {
+		v.Add(field, codeBreached, "appears in a list of breached passwords")
+	}
+}
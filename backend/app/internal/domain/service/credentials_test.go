@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"game/api/internal/errs"
+)
+
+func fieldCodes(v *errs.ValidationError) []string {
+	codes := []string{}
+	for _, f := range v.Fields {
+		codes = append(codes, f.Field+":"+f.Code)
+	}
+	return codes
+}
+
+func TestValidateUsername(t *testing.T) {
+	p := DefaultCredentialsPolicy()
+
+	tests := []struct {
+		name     string
+		username string
+		want     []string
+	}{
+		{"valid", "player_1.x-y", []string{}},
+		{"empty", "", []string{"username:required"}},
+		{"too short", "ab", []string{"username:too_short"}},
+		{"minimum length", "abc", []string{}},
+		{"maximum length", strings.Repeat("a", 30), []string{}},
+		{"too long", strings.Repeat("a", 31), []string{"username:too_long"}},
+		{"invalid characters", "bad name!", []string{"username:invalid_characters"}},
+		// o tamanho conta runas, não bytes
+		{"multibyte counts runes", "ççç", []string{"username:invalid_characters"}},
+		{"short and invalid", "a!", []string{"username:too_short", "username:invalid_characters"}},
+		{"reserved", "admin", []string{"username:reserved"}},
+		{"reserved ignores case", "Admin", []string{"username:reserved"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v errs.ValidationError
+			p.ValidateUsername(&v, tt.username)
+			if got := fieldCodes(&v); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateUsername(%q) = %v, want %v", tt.username, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	p := DefaultCredentialsPolicy()
+	p.BreachedPasswords = NewNameSet("password123")
+
+	tests := []struct {
+		name     string
+		password string
+		username string
+		want     []string
+	}{
+		{"valid", "correct horse", "player", []string{}},
+		{"empty", "", "player", []string{"password:required"}},
+		{"too short", "short", "player", []string{"password:too_short"}},
+		{"minimum length", "12345678", "player", []string{}},
+		{"bcrypt limit", strings.Repeat("a", 72), "player", []string{}},
+		{"over bcrypt limit", strings.Repeat("a", 73), "player", []string{"password:too_long"}},
+		// 8 runas, mas 24 bytes: o mínimo conta runas e o máximo conta bytes
+		{"multibyte minimum", strings.Repeat("€", 8), "player", []string{}},
+		{"multibyte over bcrypt limit", strings.Repeat("€", 25), "player", []string{"password:too_long"}},
+		{"same as username", "PlayerOne", "playerone", []string{"password:same_as_username"}},
+		{"without username", "playerone", "", []string{}},
+		{"breached", "password123", "player", []string{"password:breached"}},
+		{"breached ignores case", "PASSWORD123", "player", []string{"password:breached"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v errs.ValidationError
+			p.ValidatePassword(&v, FieldPassword, tt.password, tt.username)
+			if got := fieldCodes(&v); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidatePassword(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorIs(t *testing.T) {
+	var v errs.ValidationError
+	if v.Err() != nil {
+		t.Fatal("Err() without fields should be nil")
+	}
+	DefaultCredentialsPolicy().ValidatePassword(&v, FieldNewPassword, "", "")
+	if err := v.Err(); !errors.Is(err, errs.ErrValidation) {
+		t.Errorf("Err() = %v, want %v", err, errs.ErrValidation)
+	}
+}
+
+func TestLoadBreachedPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# comentário\nPassword123\n\n  qwerty  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := LoadBreachedPasswords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewNameSet("password123", "qwerty")
+	if !reflect.DeepEqual(set, want) {
+		t.Errorf("LoadBreachedPasswords = %v, want %v", set, want)
+	}
+
+	if _, err := LoadBreachedPasswords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadBreachedPasswords accepted a missing file")
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	transport "game/api/internal/infra/mail"
+)
+
+const (
+	FieldEmail = "email"
+
+	codeInvalidEmail = "invalid_email"
+	maxEmailLength   = 254
+)
+
+type EmailService struct {
+	clientsRepo       *repository.Clients
+	verificationsRepo *repository.OneTimeTokens
+	transport         transport.Transport
+	verificationTTL   time.Duration
+	verificationURL   string
+}
+
+func NewEmailService(
+	clientsRepo *repository.Clients,
+	verificationsRepo *repository.OneTimeTokens,
+	transport transport.Transport,
+	verificationTTL time.Duration,
+	verificationURL string,
+) *EmailService {
+	return &EmailService{
+		clientsRepo:       clientsRepo,
+		verificationsRepo: verificationsRepo,
+		transport:         transport,
+		verificationTTL:   verificationTTL,
+		verificationURL:   verificationURL,
+	}
+}
+
+func ValidateEmail(v *errs.ValidationError, email string) {
+	if email == "" {
+		v.Add(FieldEmail, codeRequired, "is required")
+		return
+	}
+	if len(email) > maxEmailLength {
+		v.Add(FieldEmail, codeTooLong, fmt.Sprintf("must be at most %d characters", maxEmailLength))
+		return
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		v.Add(FieldEmail, codeInvalidEmail, "is not a valid email address")
+	}
+}
+
+func (s *EmailService) SendVerification(ctx context.Context, client entity.Client) error {
+	if client.GetEmail() == "" {
+		return errs.ErrNotFound
+	}
+
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return err
+	}
+	err = s.verificationsRepo.Save(ctx, hash, client.GetID(), client.GetEmail(), s.verificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.transport.Send(ctx, transport.Message{
+		To:      client.GetEmail(),
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm your email address by opening the link below. It expires in %s.\n%s%s",
+			client.GetUsername(), s.verificationTTL, s.verificationURL, token,
+		),
+	})
+}
+
+func (s *EmailService) Resend(ctx context.Context, clientID uuid.UUID) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if client.EmailVerified() {
+		return nil
+	}
+	return s.SendVerification(ctx, client)
+}
+
+// Change troca o e-mail do cliente; o novo endereço volta a ficar pendente
+// de verificação.
+func (s *EmailService) Change(ctx context.Context, clientID uuid.UUID, email string) error {
+	email = strings.TrimSpace(email)
+
+	var v errs.ValidationError
+	ValidateEmail(&v, email)
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(client.GetEmail(), email) {
+		return nil
+	}
+
+	err = s.clientsRepo.UpdateEmail(ctx, client, email)
+	if err != nil {
+		return err
+	}
+
+	client, err = s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	return s.SendVerification(ctx, client)
+}
+
+func (s *EmailService) Verify(ctx context.Context, token string) error {
+	clientID, email, err := s.verificationsRepo.Consume(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return errs.ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	err = s.clientsRepo.MarkEmailVerified(ctx, client, email)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			// o e-mail foi trocado depois do envio do link
+			return errs.ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+	}).Info("Client email verified")
+	return nil
+}
+
+// RequireVerified bloqueia operações com dinheiro real para clientes que
+// ainda não confirmaram o e-mail.
+func (s *EmailService) RequireVerified(ctx context.Context, clientID uuid.UUID) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if !client.EmailVerified() {
+		return errs.ErrEmailNotVerified
+	}
+	return nil
+}
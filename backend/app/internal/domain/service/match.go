@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"math"
 	"math/rand"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 
 	"game/api/internal/application/repository"
 	"game/api/internal/domain/entity"
+	"game/api/internal/domain/event"
 	"game/api/internal/errs"
 	"game/api/internal/infra/logger"
 )
@@ -17,96 +20,164 @@ const (
 	Even      = "even"
 	Odd       = "odd"
 	MaxNumber = 100
+
+	FieldChoice = "choice"
+
+	codeInvalidChoice = "invalid_choice"
 )
 
 type MatchService struct {
-	repoPlayer *repository.Players
-	repoWallet *repository.Wallets
+	repoClients  *repository.Clients
+	repoPlayer   *repository.Players
+	repoWallet   *repository.Wallets
+	emailService *EmailService
+	events       *event.Bus
 }
 
-func NewMatchService(repoPlayer *repository.Players, repoWallet *repository.Wallets) *MatchService {
+func NewMatchService(
+	repoClients *repository.Clients,
+	repoPlayer *repository.Players,
+	repoWallet *repository.Wallets,
+	emailService *EmailService,
+	events *event.Bus,
+) *MatchService {
 	return &MatchService{
-		repoPlayer: repoPlayer,
-		repoWallet: repoWallet,
+		repoClients:  repoClients,
+		repoPlayer:   repoPlayer,
+		repoWallet:   repoWallet,
+		emailService: emailService,
+		events:       events,
 	}
 }
 
-func (s *MatchService) NewMatch(ctx context.Context, clientID uuid.UUID) error {
-	player, err := s.repoPlayer.Get(ctx, clientID)
+func (s *MatchService) NewMatch(ctx context.Context, clientID uuid.UUID) (uuid.UUID, error) {
+	if err := s.requirePlayable(ctx, clientID); err != nil {
+		return uuid.Nil, err
+	}
+
+	player, err := s.repoPlayer.Update(ctx, clientID, func(player *entity.Player) error {
+		if player.InPlay {
+			return errs.ErrPlayerAlreadyInMatch
+		}
+		player.PlayOn(uuid.New())
+		return nil
+	})
 	if err != nil {
-		logger.Errorf("Failed to get player: %v", err)
-		return err
+		if !errors.Is(err, errs.ErrPlayerAlreadyInMatch) {
+			logger.Errorf("Failed to set player in play: %v", err)
+		}
+		return uuid.Nil, err
+	}
+	return player.MatchID, nil
+}
+
+// validateBet recusa valores que, debitados, creditariam a carteira
+func validateBet(amount float64, choice string) error {
+	var v errs.ValidationError
+	if amount <= 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		v.Add(FieldAmount, codeInvalidAmount, "must be a positive number")
 	}
-	if player.InPlay {
-		return errs.ErrPlayerAlreadyInMatch
+	if choice != Even && choice != Odd {
+		v.Add(FieldChoice, codeInvalidChoice, "must be even or odd")
 	}
-	player.PlayOn()
+	return v.Err()
+}
 
-	err = s.repoPlayer.Set(ctx, &player)
+// requirePlayable recusa contas suspensas, já que conexões abertas antes da
+// suspensão podem ainda enviar jogadas, e e-mails não verificados
+func (s *MatchService) requirePlayable(ctx context.Context, clientID uuid.UUID) error {
+	client, err := s.repoClients.Get(ctx, clientID)
 	if err != nil {
-		logger.Errorf("Failed to set player in play: %v", err)
 		return err
 	}
-	return nil
+	if client.Suspended() {
+		return errs.ErrAccountSuspended
+	}
+	return s.emailService.RequireVerified(ctx, clientID)
 }
 
-func (s *MatchService) PlaceBet(ctx context.Context, playerID uuid.UUID, amount float64, choice string) (number int, result string, err error) {
-	player, err := s.repoPlayer.Get(ctx, playerID)
+// RequireMatch garante que matchID é a partida em andamento do jogador, para
+// operações que identificam a partida explicitamente (API REST).
+func (s *MatchService) RequireMatch(ctx context.Context, clientID, matchID uuid.UUID) error {
+	player, err := s.repoPlayer.Get(ctx, clientID)
 	if err != nil {
 		logger.Errorf("Failed to get player: %v", err)
-		return 0, "", err
-	}
-	if !player.HasBalance(amount) {
-		return 0, "", errs.ErrInsufficientBalance
+		return err
 	}
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	number = r.Intn(MaxNumber) + 1
-
-	if number%2 == 0 {
-		result = Even
-	} else {
-		result = Odd
+	if !player.InPlay {
+		return errs.ErrPlayerNotInMatch
 	}
-
-	if result == choice {
-		player.Credit(amount)
-		logger.Infof("Player %s won bet of %.2f", playerID, amount)
-		result = "win"
-	} else {
-		player.Debit(amount)
-		logger.Infof("Player %s lost bet of %.2f", playerID, amount)
-		result = "lose"
+	if !player.InMatch(matchID) {
+		return errs.ErrNotFound
 	}
+	return nil
+}
 
-	err = s.repoPlayer.Set(ctx, &player)
-	if err != nil {
-		logger.Errorf("Failed to update player balance: %v", err)
+func (s *MatchService) PlaceBet(ctx context.Context, playerID uuid.UUID, amount float64, choice string) (number int, result string, err error) {
+	if err = validateBet(amount, choice); err != nil {
 		return 0, "", err
 	}
-	err = s.RefreshWallet(ctx, player)
-	if err != nil {
-		logger.Errorf("Failed to refresh wallet: %v", err)
+	if err = s.requirePlayable(ctx, playerID); err != nil {
 		return 0, "", err
 	}
-	return number, result, nil
-}
-
-func (s *MatchService) RefreshWallet(ctx context.Context, player entity.Player) error {
-
-	if !player.InPlay {
-		return nil
-	}
 
-	w := entity.Wallet{
-		ClientID: player.ClientID,
-		Balance:  player.Balance,
-	}
-	err := s.repoWallet.Update(ctx, w)
+	// a carteira, a aposta e o ledger são gravados juntos sob o lock do
+	// jogador, o mesmo usado pelos ajustes manuais de saldo; se a gravação
+	// falhar a aposta é recusada e o saldo em cache não muda
+	var bet entity.Bet
+	player, err := s.repoPlayer.Update(ctx, playerID, func(player *entity.Player) error {
+		if !player.InPlay {
+			return errs.ErrPlayerNotInMatch
+		}
+		if !player.HasBalance(amount) {
+			return errs.ErrInsufficientBalance
+		}
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		number = r.Intn(MaxNumber) + 1
+
+		if number%2 == 0 {
+			result = Even
+		} else {
+			result = Odd
+		}
+
+		if result == choice {
+			player.Credit(amount)
+			logger.Infof("Player %s won bet of %.2f", playerID, amount)
+			result = "win"
+		} else {
+			player.Debit(amount)
+			logger.Infof("Player %s lost bet of %.2f", playerID, amount)
+			result = "lose"
+		}
+
+		bet = entity.Bet{
+			ID:           uuid.New(),
+			ClientID:     playerID,
+			Amount:       amount,
+			Choice:       choice,
+			Number:       number,
+			Result:       result,
+			BalanceAfter: player.Balance,
+			CreatedAt:    time.Now(),
+		}
+		wallet := entity.Wallet{
+			ClientID: player.ClientID,
+			Balance:  player.Balance,
+		}
+		return s.repoWallet.SettleBet(ctx, wallet, bet)
+	})
 	if err != nil {
-		logger.Errorf("Failed to update wallet: %v", err)
-		return err
+		if !errors.Is(err, errs.ErrInsufficientBalance) && !errors.Is(err, errs.ErrPlayerNotInMatch) {
+			logger.Errorf("Failed to settle bet: %v", err)
+		}
+		return 0, "", err
 	}
-	return nil
+
+	// fora do lock: a entrega dos eventos acessa o Redis e pode demorar
+	s.events.Publish(ctx, event.BalanceUpdated, playerID, entity.Wallet{ClientID: playerID, Balance: player.Balance})
+	s.events.Publish(ctx, event.BetSettled, playerID, bet)
+	return number, result, nil
 }
 
 func (s *MatchService) EndMatch(ctx context.Context, clientID uuid.UUID) error {
@@ -121,5 +192,6 @@ func (s *MatchService) EndMatch(ctx context.Context, clientID uuid.UUID) error {
 		return err
 	}
 	logger.Infof("Match ended for player %s with final balance %.2f", clientID, player.Balance)
+	s.events.Publish(ctx, event.MatchEnded, clientID, player)
 	return nil
 }
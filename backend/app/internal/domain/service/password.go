@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/notify"
+	"game/api/internal/infra/session"
+)
+
+type PasswordService struct {
+	clientsRepo    *repository.Clients
+	resetsRepo     *repository.OneTimeTokens
+	loginAttempts  *repository.LoginAttempts
+	sessionManager *session.Manager
+	notifier       notify.Notifier
+	credentials    CredentialsPolicy
+	resetTTL       time.Duration
+	resetURL       string
+}
+
+func NewPasswordService(
+	clientsRepo *repository.Clients,
+	resetsRepo *repository.OneTimeTokens,
+	loginAttempts *repository.LoginAttempts,
+	sessionManager *session.Manager,
+	notifier notify.Notifier,
+	credentials CredentialsPolicy,
+	resetTTL time.Duration,
+	resetURL string,
+) *PasswordService {
+	return &PasswordService{
+		clientsRepo:    clientsRepo,
+		resetsRepo:     resetsRepo,
+		loginAttempts:  loginAttempts,
+		sessionManager: sessionManager,
+		notifier:       notifier,
+		credentials:    credentials,
+		resetTTL:       resetTTL,
+		resetURL:       resetURL,
+	}
+}
+
+// Change troca a senha do cliente autenticado e encerra todas as outras
+// sessões, mantendo apenas a que fez a troca.
+func (s *PasswordService) Change(ctx context.Context, clientID uuid.UUID, currentToken, currentPassword, newPassword string) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if !client.CheckPasswordHash(currentPassword) {
+		return errs.ErrInvalidPassword
+	}
+
+	var v errs.ValidationError
+	s.credentials.ValidatePassword(&v, FieldNewPassword, newPassword, client.GetUsername())
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	err = client.SetPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	err = s.clientsRepo.UpdatePassword(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sessionManager.RevokeAll(ctx, clientID.String(), currentToken)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+	}).Info("Client password changed")
+	return nil
+}
+
+const (
+	// intervalo mínimo entre pedidos de redefinição para o mesmo username
+	resetCooldown = time.Minute
+	// o envio sai da requisição; este é o prazo para concluí-lo
+	resetSendTimeout = 30 * time.Second
+)
+
+// RequestReset envia o token de redefinição ao cliente. Não retorna erro
+// quando o username não existe, para não revelar quais contas existem: a
+// busca da conta e o envio acontecem fora da requisição, que faz o mesmo
+// trabalho para qualquer username. Um novo pedido invalida o token anterior.
+func (s *PasswordService) RequestReset(ctx context.Context, username string) error {
+	ok, err := s.resetsRepo.Cooldown(ctx, strings.ToLower(username), resetCooldown)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logger.WithFields(logrus.Fields{
+			"username": username,
+		}).Warn("Password reset requested again during cooldown")
+		return nil
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resetSendTimeout)
+		defer cancel()
+		if err := s.sendReset(ctx, username); err != nil {
+			logger.WithFields(logrus.Fields{
+				"username": username,
+			}).Errorf("Failed to send password reset: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *PasswordService) sendReset(ctx context.Context, username string) error {
+	client, err := s.clientsRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			logger.WithFields(logrus.Fields{
+				"username": username,
+			}).Warn("Password reset requested for unknown username")
+			return nil
+		}
+		return err
+	}
+	if client.Suspended() {
+		logger.WithFields(logrus.Fields{
+			"client_id": client.GetID(),
+		}).Warn("Password reset requested for suspended account")
+		return nil
+	}
+
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	err = s.resetsRepo.Save(ctx, hash, client.GetID(), "", s.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, notify.Notification{
+		ClientID: client.GetID().String(),
+		Username: client.GetUsername(),
+		Email:    client.GetEmail(),
+		Subject:  "Password reset",
+		Body: fmt.Sprintf(
+			"Use the link below to reset your password. It expires in %s and can be used only once.\n%s%s",
+			s.resetTTL, s.resetURL, token,
+		),
+	})
+}
+
+func (s *PasswordService) Reset(ctx context.Context, token, newPassword string) error {
+	// valida antes de consumir o token, para que o cliente possa tentar de novo
+	var v errs.ValidationError
+	s.credentials.ValidatePassword(&v, FieldNewPassword, newPassword, "")
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	clientID, _, err := s.resetsRepo.Consume(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return errs.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// contas encerradas não são encontradas
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return errs.ErrInvalidResetToken
+		}
+		return err
+	}
+	if client.Suspended() {
+		return errs.ErrAccountSuspended
+	}
+
+	err = client.SetPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	err = s.clientsRepo.UpdatePassword(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sessionManager.RevokeAll(ctx, clientID.String(), "")
+	if err != nil {
+		return err
+	}
+	err = s.loginAttempts.Reset(ctx, repository.LoginScopeUsername, client.GetUsername())
+	if err != nil {
+		logger.Errorf("Failed to reset login attempts: %v", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+	}).Info("Client password reset")
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	exportPageSize = 500
+
+	erasedUsernamePrefix = "erased-"
+	// não é um hash bcrypt válido, então nenhuma senha confere
+	erasedPassword = "!"
+)
+
+type DataExport struct {
+	Client       entity.Client
+	Wallet       entity.Wallet
+	Sessions     []session.Session
+	Bets         []entity.Bet
+	Transactions []entity.LedgerEntry
+	GeneratedAt  time.Time
+}
+
+// PrivacyService atende pedidos de portabilidade (exportação) e de
+// esquecimento (anonimização) dos dados pessoais do cliente.
+type PrivacyService struct {
+	clientsRepo    *repository.Clients
+	walletRepo     *repository.Wallets
+	betsRepo       *repository.Bets
+	ledgerRepo     *repository.Ledger
+	accountService *AccountService
+	sessionManager *session.Manager
+}
+
+func NewPrivacyService(
+	clientsRepo *repository.Clients,
+	walletRepo *repository.Wallets,
+	betsRepo *repository.Bets,
+	ledgerRepo *repository.Ledger,
+	accountService *AccountService,
+	sessionManager *session.Manager,
+) *PrivacyService {
+	return &PrivacyService{
+		clientsRepo:    clientsRepo,
+		walletRepo:     walletRepo,
+		betsRepo:       betsRepo,
+		ledgerRepo:     ledgerRepo,
+		accountService: accountService,
+		sessionManager: sessionManager,
+	}
+}
+
+// Export inclui contas encerradas: o direito à portabilidade continua valendo
+// depois do encerramento.
+func (s *PrivacyService) Export(ctx context.Context, clientID uuid.UUID) (export DataExport, err error) {
+	export.GeneratedAt = time.Now().UTC()
+
+	if export.Client, err = s.clientsRepo.GetIncludingClosed(ctx, clientID); err != nil {
+		return
+	}
+	if export.Wallet, err = s.walletRepo.Get(ctx, clientID); err != nil {
+		return
+	}
+	if export.Sessions, err = s.sessionManager.List(ctx, clientID.String()); err != nil {
+		return
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		bets, err := s.betsRepo.ListByClient(ctx, clientID, exportPageSize, offset)
+		if err != nil {
+			return export, err
+		}
+		export.Bets = append(export.Bets, bets...)
+		if len(bets) < exportPageSize {
+			break
+		}
+	}
+	for offset := 0; ; offset += exportPageSize {
+		entries, err := s.ledgerRepo.ListByClient(ctx, clientID, exportPageSize, offset)
+		if err != nil {
+			return export, err
+		}
+		export.Transactions = append(export.Transactions, entries...)
+		if len(entries) < exportPageSize {
+			break
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+	}).Info("Client data exported")
+	return
+}
+
+// Erase anonimiza username, e-mail, 2FA e histórico de login e encerra a
+// conta. Carteira, apostas e ledger são mantidos por obrigação legal,
+// vinculados apenas ao id.
+func (s *PrivacyService) Erase(ctx context.Context, actorID, clientID uuid.UUID) error {
+	if err := s.accountService.signOut(ctx, clientID); err != nil {
+		return err
+	}
+	err := s.clientsRepo.Erase(ctx, clientID, erasedUsernamePrefix+clientID.String(), erasedPassword)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"actor_id":  actorID,
+		"client_id": clientID,
+	}).Info("Client personal data erased")
+	return nil
+}
+
+func (s *PrivacyService) EraseOwn(ctx context.Context, clientID uuid.UUID, password string) error {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if !client.CheckPasswordHash(password) {
+		return errs.ErrInvalidPassword
+	}
+	return s.Erase(ctx, clientID, clientID)
+}
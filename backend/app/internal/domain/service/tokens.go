@@ -0,0 +1,27 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	oneTimeTokenBytes = 32
+)
+
+// newOneTimeToken gera o token entregue ao cliente e o hash que é persistido
+func newOneTimeToken() (token, hash string, err error) {
+	buf := make([]byte, oneTimeTokenBytes)
+	if _, err = rand.Read(buf); err != nil {
+		return
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
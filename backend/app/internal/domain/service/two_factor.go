@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/application/repository"
+	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5
+	totpSkew          = 1
+)
+
+type TwoFactorService struct {
+	clientsRepo   *repository.Clients
+	twoFactorRepo *repository.TwoFactor
+	issuer        string
+}
+
+func NewTwoFactorService(clientsRepo *repository.Clients, twoFactorRepo *repository.TwoFactor, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		clientsRepo:   clientsRepo,
+		twoFactorRepo: twoFactorRepo,
+		issuer:        issuer,
+	}
+}
+
+// Enroll gera um novo segredo ainda não habilitado; ele só passa a valer
+// depois que o cliente confirma um código em Enable.
+func (s *TwoFactorService) Enroll(ctx context.Context, clientID uuid.UUID) (secret, uri string, err error) {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return
+	}
+	if client.TOTPEnabled() {
+		err = errs.ErrMFAAlreadyEnabled
+		return
+	}
+
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		return
+	}
+	err = s.clientsRepo.SetTOTP(ctx, client, secret, false)
+	if err != nil {
+		return
+	}
+
+	uri = totp.URI(s.issuer, client.GetUsername(), secret)
+	return
+}
+
+func (s *TwoFactorService) Enable(ctx context.Context, clientID uuid.UUID, code string) (recoveryCodes []string, err error) {
+	client, err := s.clientsRepo.Get(ctx, clientID)
+	if err != nil {
+		return
+	}
+	if client.TOTPEnabled() {
+		err = errs.ErrMFAAlreadyEnabled
+		return
+	}
+	if client.GetTOTPSecret() == "" {
+		err = errs.ErrMFANotEnrolled
+		return
+	}
+	if err = s.verifyTOTP(ctx, client, code); err != nil {
+		return
+	}
+
+	recoveryCodes, err = s.replaceRecoveryCodes(ctx, clientID)
+	if err != nil {
+		return
+	}
+	err = s.clientsRepo.SetTOTP(ctx, client, client.GetTOTPSecret(), true)
+	if err != nil {
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+	}).Info("Two-factor authentication enabled")
+	return
+}
+
+// Disable e RegenerateRecoveryCodes não conferem o código; o chamador deve
+// fazê-lo antes, com o controle de tentativas (AuthService).
+func (s *TwoFactorService) Disable(ctx context.Context, client entity.Client) error {
+	clientID := client.GetID()
+	err := s.clientsRepo.SetTOTP(ctx, client, "", false)
+	if err != nil {
+		return err
+	}
+	err = s.twoFactorRepo.DeleteRecoveryCodes(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+	}).Info("Two-factor authentication disabled")
+	return nil
+}
+
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, clientID uuid.UUID) ([]string, error) {
+	return s.replaceRecoveryCodes(ctx, clientID)
+}
+
+// Verify aceita tanto um código TOTP quanto um código de recuperação
+func (s *TwoFactorService) Verify(ctx context.Context, client entity.Client, code string) error {
+	if !client.TOTPEnabled() {
+		return errs.ErrMFANotEnrolled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(ctx, client, code)
+	}
+
+	ok, err := s.twoFactorRepo.UseRecoveryCode(ctx, client.GetID(), hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrInvalidMFACode
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": client.GetID(),
+	}).Warn("Recovery code used")
+	return nil
+}
+
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, client entity.Client, code string) error {
+	step, ok := totp.Validate(client.GetTOTPSecret(), code, time.Now(), totpSkew)
+	if !ok {
+		return errs.ErrInvalidMFACode
+	}
+
+	fresh, err := s.twoFactorRepo.MarkStepUsed(ctx, client.GetID(), step, time.Duration(2*totpSkew+1)*totp.Period)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return errs.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, clientID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, clientID, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,12 +1,89 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
 
 var (
-	ErrUsernameExists       = errors.New("username already exists")
-	ErrInvalidPassword      = errors.New("invalid password")
-	ErrNotFound             = errors.New("not found")
-	ErrInsufficientBalance  = errors.New("insufficient balance")
-	ErrPlayerAlreadyInMatch = errors.New("player already in match")
-	ErrPlayerNotInMatch     = errors.New("player not in match")
+	ErrUsernameExists           = errors.New("username already exists")
+	ErrEmailExists              = errors.New("email already exists")
+	ErrEmailNotVerified         = errors.New("email not verified")
+	ErrInvalidPassword          = errors.New("invalid password")
+	ErrInvalidCredentials       = errors.New("invalid username or password")
+	ErrInvalidResetToken        = errors.New("invalid or expired reset token")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrTooManyAttempts          = errors.New("too many attempts")
+	ErrRateLimited              = errors.New("rate limit exceeded")
+	ErrInvalidAPIKey            = errors.New("invalid api key")
+	ErrAccountSuspended         = errors.New("account suspended")
+	ErrAccountClosed            = errors.New("account closed")
+	ErrForbidden                = errors.New("forbidden")
+	ErrMFARequired              = errors.New("two-factor verification required")
+	ErrInvalidMFACode           = errors.New("invalid two-factor code")
+	ErrMFANotEnrolled           = errors.New("two-factor authentication not enrolled")
+	ErrMFAAlreadyEnabled        = errors.New("two-factor authentication already enabled")
+	ErrValidation               = errors.New("validation failed")
+	ErrNotFound                 = errors.New("not found")
+	ErrInsufficientBalance      = errors.New("insufficient balance")
+	ErrPlayerAlreadyInMatch     = errors.New("player already in match")
+	ErrPlayerNotInMatch         = errors.New("player not in match")
 )
+
+// RetryError indica que a operação foi recusada temporariamente e pode ser
+// repetida após RetryAfter.
+type RetryError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Seconds é o valor de Retry-After: segundos inteiros, arredondados para cima
+// para que o cliente não tente antes da hora
+func (e *RetryError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError agrupa os erros de validação por campo da requisição
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err retorna nil quando nenhum campo falhou
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,29 @@
+package errs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryErrorSeconds(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       int
+	}{
+		{0, 0},
+		{time.Millisecond, 1},
+		{999 * time.Millisecond, 1},
+		{time.Second, 1},
+		{time.Second + time.Nanosecond, 2},
+		{1500 * time.Millisecond, 2},
+		{time.Minute, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.retryAfter.String(), func(t *testing.T) {
+			err := &RetryError{Err: ErrRateLimited, RetryAfter: tt.retryAfter}
+			if got := err.Seconds(); got != tt.want {
+				t.Errorf("Seconds() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
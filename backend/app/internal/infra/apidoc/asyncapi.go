@@ -0,0 +1,87 @@
+package apidoc
+
+const (
+	asyncAPIVersion = "2.6.0"
+	asyncAPIRef     = "#/components/schemas/"
+)
+
+// Envelope é a estrutura comum às mensagens de uma direção do canal: NameField
+// identifica a mensagem e BodyField traz o corpo específico dela.
+type Envelope struct {
+	Type      interface{}
+	NameField string
+	BodyField string
+}
+
+type Message struct {
+	// chave única em components.messages
+	Key     string
+	Name    string
+	Summary string
+	// corpo da mensagem, em Envelope.BodyField; nil quando não há corpo
+	Payload interface{}
+}
+
+// Channel descreve um canal WebSocket. Publish são as mensagens enviadas pelo
+// cliente e Subscribe as recebidas do servidor (semântica da AsyncAPI 2).
+type Channel struct {
+	Path        string
+	Description string
+
+	PublishEnvelope   Envelope
+	Publish           []Message
+	SubscribeEnvelope Envelope
+	Subscribe         []Message
+}
+
+// AsyncAPI gera o documento AsyncAPI 2 dos canais
+func AsyncAPI(info Info, channels []Channel) map[string]interface{} {
+	schemas := NewSchemas(asyncAPIRef)
+	messages := make(map[string]interface{})
+	docs := make(map[string]interface{}, len(channels))
+
+	for _, ch := range channels {
+		docs[ch.Path] = map[string]interface{}{
+			"description": ch.Description,
+			"publish":     direction(schemas, messages, ch.PublishEnvelope, ch.Publish),
+			"subscribe":   direction(schemas, messages, ch.SubscribeEnvelope, ch.Subscribe),
+		}
+	}
+
+	return map[string]interface{}{
+		"asyncapi":           asyncAPIVersion,
+		"info":               info,
+		"defaultContentType": "application/json",
+		"channels":           docs,
+		"components": map[string]interface{}{
+			"messages": messages,
+			"schemas":  schemas.Components(),
+		},
+	}
+}
+
+func direction(schemas *Schemas, messages map[string]interface{}, env Envelope, list []Message) map[string]interface{} {
+	refs := make([]map[string]string, 0, len(list))
+	for _, m := range list {
+		messages[m.Key] = map[string]interface{}{
+			"name":    m.Name,
+			"summary": m.Summary,
+			"payload": envelopeSchema(schemas, env, m),
+		}
+		refs = append(refs, map[string]string{"$ref": "#/components/messages/" + m.Key})
+	}
+	return map[string]interface{}{
+		"message": map[string]interface{}{"oneOf": refs},
+	}
+}
+
+// envelopeSchema fixa o nome da mensagem e troca o corpo genérico do
+// envelope pelo schema do payload
+func envelopeSchema(schemas *Schemas, env Envelope, m Message) *Schema {
+	s := schemas.Inline(env.Type)
+	s.Properties[env.NameField] = &Schema{Type: "string", Enum: []string{m.Name}}
+	if m.Payload != nil {
+		s.Properties[env.BodyField] = schemas.Of(m.Payload)
+	}
+	return s
+}
@@ -0,0 +1,236 @@
+package apidoc
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+	openAPIRef     = "#/components/schemas/"
+
+	bearerScheme = "bearerAuth"
+	apiKeyScheme = "apiKeyAuth"
+)
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Auth é a autenticação exigida por uma rota
+type Auth int
+
+const (
+	AuthNone Auth = iota
+	// JWT de sessão (inclusive o token parcial do login com 2FA)
+	AuthSession
+	// JWT de sessão ou chave de API com Operation.Scope
+	AuthSessionOrAPIKey
+)
+
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+type Response struct {
+	Status      int
+	Description string
+	// nil: sem corpo (2xx) ou mensagem de texto (erros de http.Error)
+	Body interface{}
+	// padrão application/json
+	ContentType string
+}
+
+// Operation descreve uma rota REST. Parâmetros de caminho vêm de Path.
+type Operation struct {
+	Method    string
+	Path      string
+	Summary   string
+	Tag       string
+	Auth      Auth
+	Scope     string
+	Query     []Param
+	Request   interface{}
+	Responses []Response
+}
+
+func (o Operation) Key() string {
+	return o.Method + " " + o.Path
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// OpenAPI gera o documento OpenAPI 3 das operações
+func OpenAPI(info Info, ops []Operation) map[string]interface{} {
+	schemas := NewSchemas(openAPIRef)
+	paths := make(map[string]map[string]interface{})
+	for _, op := range ops {
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]interface{})
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = operation(schemas, op)
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info":    info,
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.Components(),
+			"securitySchemes": map[string]interface{}{
+				bearerScheme: map[string]string{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				apiKeyScheme: map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+}
+
+func operation(schemas *Schemas, op Operation) map[string]interface{} {
+	doc := map[string]interface{}{
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+		"responses":   responses(schemas, op),
+		"security":    security(op),
+	}
+	if op.Tag != "" {
+		doc["tags"] = []string{op.Tag}
+	}
+
+	var params []map[string]interface{}
+	for _, m := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   &Schema{Type: "string"},
+		})
+	}
+	for _, q := range op.Query {
+		params = append(params, map[string]interface{}{
+			"name":        q.Name,
+			"in":          "query",
+			"required":    q.Required,
+			"description": q.Description,
+			"schema":      &Schema{Type: "string"},
+		})
+	}
+	if params != nil {
+		doc["parameters"] = params
+	}
+
+	if op.Request != nil {
+		doc["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schemas.Of(op.Request)},
+			},
+		}
+	}
+	return doc
+}
+
+func responses(schemas *Schemas, op Operation) map[string]interface{} {
+	list := op.Responses
+	if op.Request != nil && !hasStatus(list, http.StatusBadRequest) {
+		list = append(list, Response{Status: http.StatusBadRequest, Description: "Invalid request body"})
+	}
+	if op.Auth != AuthNone && !hasStatus(list, http.StatusUnauthorized) {
+		list = append(list, Response{Status: http.StatusUnauthorized, Description: "Missing or invalid credentials"})
+	}
+
+	res := make(map[string]interface{}, len(list))
+	for _, r := range list {
+		doc := map[string]interface{}{"description": r.Description}
+		contentType, schema := r.ContentType, schemas.Of(r.Body)
+		switch {
+		case r.Body != nil && contentType == "":
+			contentType = "application/json"
+		case r.Body == nil && contentType == "" && r.Status >= http.StatusBadRequest:
+			contentType, schema = "text/plain", &Schema{Type: "string"}
+		}
+		if contentType != "" {
+			if schema == nil {
+				schema = &Schema{Type: "string", Format: "binary"}
+			}
+			doc["content"] = map[string]interface{}{
+				contentType: map[string]interface{}{"schema": schema},
+			}
+		}
+		res[strconv.Itoa(r.Status)] = doc
+	}
+	return res
+}
+
+func hasStatus(list []Response, status int) bool {
+	for _, r := range list {
+		if r.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func security(op Operation) []map[string][]string {
+	switch op.Auth {
+	case AuthSession:
+		return []map[string][]string{{bearerScheme: {}}}
+	case AuthSessionOrAPIKey:
+		scopes := []string{}
+		if op.Scope != "" {
+			scopes = []string{op.Scope}
+		}
+		return []map[string][]string{{bearerScheme: {}}, {apiKeyScheme: scopes}}
+	default:
+		return []map[string][]string{}
+	}
+}
+
+// operationID deriva um identificador estável do método e do caminho, por
+// exemplo POST /matches/{id}/bets -> postMatchesIdBets
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, part := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Diff compara as rotas registradas com as documentadas: missing são rotas
+// sem documentação e stale, documentação de rotas que não existem.
+func Diff(routes []Route, ops []Operation) (missing, stale []string) {
+	documented := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		documented[op.Key()] = true
+	}
+	registered := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		key := r.Method + " " + r.Path
+		registered[key] = true
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	return missing, stale
+}
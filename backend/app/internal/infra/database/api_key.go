@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+const apiKeyColumns = "guid, client_id, name, prefix, key_hash, scopes, rate_limit, created_at, revoked_at"
+
+type APIKeyData struct {
+	GUID      string         `db:"guid" json:"guid"`
+	ClientID  string         `db:"client_id" json:"client_id"`
+	Name      string         `db:"name" json:"name"`
+	Prefix    string         `db:"prefix" json:"prefix"`
+	KeyHash   string         `db:"key_hash" json:"key_hash"`
+	Scopes    pq.StringArray `db:"scopes" json:"scopes"`
+	RateLimit int            `db:"rate_limit" json:"rate_limit"`
+	CreatedAt string         `db:"created_at" json:"created_at"`
+	RevokedAt *string        `db:"revoked_at" json:"revoked_at"`
+}
+
+func (k *APIKeyData) MarshalBinary() ([]byte, error) {
+	return json.Marshal(k)
+}
+
+func (k *APIKeyData) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, k)
+}
+
+func (pg *Postgres) InsertAPIKey(ctx context.Context, k APIKeyData) error {
+	logger.WithFields(logrus.Fields{
+		"clientID": k.ClientID,
+		"keyID":    k.GUID,
+	}).Debug("Inserting API key")
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (guid, client_id, name, prefix, key_hash, scopes, rate_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		DB_TABLE_API_KEYS,
+	)
+	_, err := pg.db.ExecContext(ctx, query, k.GUID, k.ClientID, k.Name, k.Prefix, k.KeyHash, k.Scopes, k.RateLimit)
+	if err != nil {
+		logger.Errorf("Failed to insert API key: %v", err)
+	}
+	return err
+}
+
+func (pg *Postgres) FindAPIKeyByHash(ctx context.Context, hash string) (k APIKeyData, err error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE key_hash = $1 and revoked_at IS NULL", apiKeyColumns, DB_TABLE_API_KEYS)
+	err = pg.db.GetContext(ctx, &k, q, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errs.ErrNotFound
+	} else if err != nil {
+		logger.Errorf("Failed to find API key: %v", err)
+	}
+	return
+}
+
+func (pg *Postgres) FindAPIKeysByClientID(ctx context.Context, clientID string) (keys []APIKeyData, err error) {
+	q := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE client_id = $1 and revoked_at IS NULL ORDER BY created_at",
+		apiKeyColumns,
+		DB_TABLE_API_KEYS,
+	)
+
+	keys = []APIKeyData{}
+	err = pg.db.SelectContext(ctx, &keys, q, clientID)
+	if err != nil {
+		logger.Errorf("Failed to find API keys: %v", err)
+	}
+	return
+}
+
+// RevokeAPIKey revoga a chave do cliente e retorna o hash para limpeza do cache
+func (pg *Postgres) RevokeAPIKey(ctx context.Context, clientID, keyID string) (hash string, err error) {
+	q := fmt.Sprintf(
+		"UPDATE %s SET revoked_at = NOW() WHERE guid = $1 and client_id = $2 and revoked_at IS NULL RETURNING key_hash",
+		DB_TABLE_API_KEYS,
+	)
+	err = pg.db.GetContext(ctx, &hash, q, keyID, clientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errs.ErrNotFound
+		return
+	} else if err != nil {
+		logger.Errorf("Failed to revoke API key: %v", err)
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+		"keyID":    keyID,
+	}).Info("API key revoked")
+	return
+}
+
+// RevokeAPIKeysByClientID revoga todas as chaves ativas do cliente e retorna
+// os IDs e hashes, para limpeza do cache e encerramento das conexões
+func (pg *Postgres) RevokeAPIKeysByClientID(ctx context.Context, clientID string) (keys []APIKeyData, err error) {
+	q := fmt.Sprintf(
+		"UPDATE %s SET revoked_at = NOW() WHERE client_id = $1 and revoked_at IS NULL RETURNING guid, key_hash",
+		DB_TABLE_API_KEYS,
+	)
+
+	keys = []APIKeyData{}
+	err = pg.db.SelectContext(ctx, &keys, q, clientID)
+	if err != nil {
+		logger.Errorf("Failed to revoke API keys: %v", err)
+		return
+	}
+
+	if len(keys) > 0 {
+		logger.WithFields(logrus.Fields{
+			"clientID": clientID,
+			"revoked":  len(keys),
+		}).Info("API keys revoked")
+	}
+	return
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	adjustmentColumns = "guid, client_id, amount, reason, status, requested_by, decided_by, created_at, decided_at"
+
+	adjustmentPending  = "pending"
+	adjustmentApplied  = "applied"
+	adjustmentRejected = "rejected"
+	ledgerAdjustment   = "adjustment"
+)
+
+type AdjustmentData struct {
+	GUID        string  `db:"guid" json:"guid"`
+	ClientID    string  `db:"client_id" json:"client_id"`
+	Amount      float64 `db:"amount" json:"amount"`
+	Reason      string  `db:"reason" json:"reason"`
+	Status      string  `db:"status" json:"status"`
+	RequestedBy string  `db:"requested_by" json:"requested_by"`
+	DecidedBy   *string `db:"decided_by" json:"decided_by"`
+	CreatedAt   string  `db:"created_at" json:"created_at"`
+	DecidedAt   *string `db:"decided_at" json:"decided_at"`
+}
+
+func (pg *Postgres) InsertAdjustment(ctx context.Context, a AdjustmentData) error {
+	logger.WithFields(logrus.Fields{
+		"clientID":    a.ClientID,
+		"requestedBy": a.RequestedBy,
+		"amount":      a.Amount,
+	}).Debug("Inserting balance adjustment")
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (guid, client_id, amount, reason, status, requested_by) VALUES ($1, $2, $3, $4, $5, $6)",
+		DB_TABLE_ADJUSTMENTS,
+	)
+	_, err := pg.db.ExecContext(ctx, query, a.GUID, a.ClientID, a.Amount, a.Reason, adjustmentPending, a.RequestedBy)
+	if err != nil {
+		logger.Errorf("Failed to insert balance adjustment: %v", err)
+	}
+	return err
+}
+
+func (pg *Postgres) FindAdjustment(ctx context.Context, id string) (a AdjustmentData, err error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE guid = $1", adjustmentColumns, DB_TABLE_ADJUSTMENTS)
+	err = pg.db.GetContext(ctx, &a, q, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errs.ErrNotFound
+	} else if err != nil {
+		logger.Errorf("Failed to find balance adjustment: %v", err)
+	}
+	return
+}
+
+func (pg *Postgres) FindAdjustmentsByStatus(ctx context.Context, status string, limit, offset int) (adjustments []AdjustmentData, err error) {
+	q := fmt.Sprintf(
+		`SELECT %s FROM %s
+		WHERE status = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		adjustmentColumns,
+		DB_TABLE_ADJUSTMENTS,
+	)
+
+	adjustments = []AdjustmentData{}
+	err = pg.db.SelectContext(ctx, &adjustments, q, status, limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to find balance adjustments: %v", err)
+	}
+	return
+}
+
+// ApplyAdjustment credita/debita a carteira e registra o lançamento no ledger
+// numa única transação. Só ajustes pendentes podem ser aplicados; o saldo
+// resultante não pode ficar negativo.
+func (pg *Postgres) ApplyAdjustment(ctx context.Context, id, decidedBy string) (a AdjustmentData, balance float64, err error) {
+	tx, err := pg.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.Errorf("Failed to begin transaction: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE guid = $1 AND status = $2 FOR UPDATE", adjustmentColumns, DB_TABLE_ADJUSTMENTS)
+	err = tx.GetContext(ctx, &a, q, id, adjustmentPending)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errs.ErrNotFound
+		return
+	} else if err != nil {
+		logger.Errorf("Failed to lock balance adjustment: %v", err)
+		return
+	}
+
+	q = fmt.Sprintf("SELECT balance FROM %s WHERE client_id = $1 AND deleted_at IS NULL FOR UPDATE", DB_TABLE_WALLETS)
+	err = tx.GetContext(ctx, &balance, q, a.ClientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errs.ErrNotFound
+		return
+	} else if err != nil {
+		logger.Errorf("Failed to lock wallet: %v", err)
+		return
+	}
+
+	balance += a.Amount
+	if balance < 0 {
+		err = errs.ErrInsufficientBalance
+		return
+	}
+
+	q = fmt.Sprintf("UPDATE %s SET balance = $1, updated_at = NOW() WHERE client_id = $2", DB_TABLE_WALLETS)
+	if _, err = tx.ExecContext(ctx, q, balance, a.ClientID); err != nil {
+		logger.Errorf("Failed to update wallet: %v", err)
+		return
+	}
+
+	// approved_by só é preenchido quando um segundo admin aprovou o ajuste
+	var approvedBy *string
+	if decidedBy != a.RequestedBy {
+		approvedBy = &decidedBy
+	}
+	reason := a.Reason
+	err = insertLedgerEntry(ctx, tx, LedgerEntryData{
+		GUID:         uuid.New().String(),
+		ClientID:     a.ClientID,
+		EntryType:    ledgerAdjustment,
+		Amount:       a.Amount,
+		BalanceAfter: balance,
+		ReferenceID:  a.GUID,
+		Reason:       &reason,
+		CreatedBy:    &a.RequestedBy,
+		ApprovedBy:   approvedBy,
+	})
+	if err != nil {
+		return
+	}
+
+	q = fmt.Sprintf("UPDATE %s SET status = $1, decided_by = $2, decided_at = NOW() WHERE guid = $3", DB_TABLE_ADJUSTMENTS)
+	if _, err = tx.ExecContext(ctx, q, adjustmentApplied, decidedBy, a.GUID); err != nil {
+		logger.Errorf("Failed to update balance adjustment: %v", err)
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	a.Status = adjustmentApplied
+	a.DecidedBy = &decidedBy
+	logger.WithFields(logrus.Fields{
+		"adjustmentID": a.GUID,
+		"clientID":     a.ClientID,
+		"amount":       a.Amount,
+		"requestedBy":  a.RequestedBy,
+		"decidedBy":    decidedBy,
+	}).Info("Balance adjustment applied")
+	return
+}
+
+func (pg *Postgres) RejectAdjustment(ctx context.Context, id, decidedBy string) error {
+	q := fmt.Sprintf(
+		"UPDATE %s SET status = $1, decided_by = $2, decided_at = NOW() WHERE guid = $3 AND status = $4",
+		DB_TABLE_ADJUSTMENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, q, adjustmentRejected, decidedBy, id, adjustmentPending)
+	if err != nil {
+		logger.Errorf("Failed to reject balance adjustment: %v", err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+type BetData struct {
+	GUID         string  `db:"guid" json:"guid"`
+	ClientID     string  `db:"client_id" json:"client_id"`
+	Amount       float64 `db:"amount" json:"amount"`
+	Choice       string  `db:"choice" json:"choice"`
+	Number       int     `db:"number" json:"number"`
+	Result       string  `db:"result" json:"result"`
+	BalanceAfter float64 `db:"balance_after" json:"balance_after"`
+	CreatedAt    string  `db:"created_at" json:"created_at"`
+}
+
+// SettleBet grava o novo saldo da carteira, a aposta e o lançamento
+// correspondente no ledger na mesma transação, para que o histórico nunca
+// divirja do saldo.
+func (pg *Postgres) SettleBet(ctx context.Context, wallet WalletData, b BetData, entry LedgerEntryData) error {
+	logger.WithFields(logrus.Fields{
+		"clientID": b.ClientID,
+		"betID":    b.GUID,
+	}).Debug("Settling bet")
+
+	tx, err := pg.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.Errorf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET balance = $1, updated_at = NOW() WHERE client_id = $2 AND deleted_at IS NULL",
+		DB_TABLE_WALLETS,
+	)
+	res, err := tx.ExecContext(ctx, query, wallet.Balance, wallet.ClientID)
+	if err != nil {
+		logger.Errorf("Failed to update wallet: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (guid, client_id, amount, choice, number, result, balance_after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		DB_TABLE_BETS,
+	)
+	_, err = tx.ExecContext(ctx, query, b.GUID, b.ClientID, b.Amount, b.Choice, b.Number, b.Result, b.BalanceAfter)
+	if err != nil {
+		logger.Errorf("Failed to insert bet: %v", err)
+		return err
+	}
+	if err = insertLedgerEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (pg *Postgres) FindBetsByClientID(ctx context.Context, clientID string, limit, offset int) (bets []BetData, err error) {
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Debug("Searching bets by client ID")
+
+	q := fmt.Sprintf(
+		`SELECT guid, client_id, amount, choice, number, result, balance_after, created_at
+		FROM %s
+		WHERE client_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		DB_TABLE_BETS,
+	)
+
+	bets = []BetData{}
+	err = pg.db.SelectContext(ctx, &bets, q, clientID, limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to find bets: %v", err)
+	}
+	return
+}
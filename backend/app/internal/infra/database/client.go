@@ -1,17 +1,26 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"game/api/internal/errs"
 	"game/api/internal/infra/logger"
+	"strings"
 
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	clientColumns = `guid, username, password, created_at, updated_at, deleted_at,
+	totp_secret, totp_enabled_at, email, email_verified_at, role, suspended_at`
+
+	clientEmailIndex = "idx_clients_email_lower"
+)
+
 type ClientData struct {
 	GUID      string  `db:"guid" json:"guid"`
 	Username  string  `db:"username" json:"username"`
@@ -19,6 +28,15 @@ type ClientData struct {
 	CreatedAt string  `db:"created_at" json:"created_at"`
 	UpdatedAt string  `db:"updated_at" json:"updated_at"`
 	DeletedAt *string `db:"deleted_at" json:"deleted_at"`
+
+	TOTPSecret    *string `db:"totp_secret" json:"totp_secret"`
+	TOTPEnabledAt *string `db:"totp_enabled_at" json:"totp_enabled_at"`
+
+	Email           *string `db:"email" json:"email"`
+	EmailVerifiedAt *string `db:"email_verified_at" json:"email_verified_at"`
+
+	Role        string  `db:"role" json:"role"`
+	SuspendedAt *string `db:"suspended_at" json:"suspended_at"`
 }
 
 func (c *ClientData) MarshalBinary() ([]byte, error) {
@@ -34,17 +52,21 @@ func (pg *Postgres) InsertClient(c ClientData) error {
 		"username": c.Username,
 	}).Debug("Inserting new client")
 
-	query := fmt.Sprintf("INSERT INTO %s (guid, username, password) VALUES ($1, $2, $3)", DB_TABLE_CLIENTS)
+	query := fmt.Sprintf("INSERT INTO %s (guid, username, password, email) VALUES ($1, $2, $3, $4)", DB_TABLE_CLIENTS)
 	_, err := pg.db.Exec(
 		query,
 		c.GUID,
 		c.Username,
 		c.Password,
+		c.Email,
 	)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) {
 			if pqErr.Code == "23505" {
+				if pqErr.Constraint == clientEmailIndex {
+					return errs.ErrEmailExists
+				}
 				return errs.ErrUsernameExists
 			}
 		}
@@ -64,9 +86,10 @@ func (pg *Postgres) FindClientByID(clientID string) (client ClientData, err erro
 	}).Debug("Searching for client by ID")
 
 	q := fmt.Sprintf(
-		`SELECT guid, username, password, created_at, updated_at, deleted_at
+		`SELECT %s
 		FROM %s 
 		WHERE guid = $1 and deleted_at IS NULL`,
+		clientColumns,
 		DB_TABLE_CLIENTS,
 	)
 
@@ -95,9 +118,10 @@ func (pg *Postgres) FindClientByUsername(username string) (client ClientData, er
 	}).Debug("Searching for client by username")
 
 	q := fmt.Sprintf(
-		`SELECT guid, username, password, created_at, updated_at, deleted_at
+		`SELECT %s
 		FROM %s 
-		WHERE username = $1 and deleted_at IS NULL`,
+		WHERE LOWER(username) = LOWER($1) and deleted_at IS NULL`,
+		clientColumns,
 		DB_TABLE_CLIENTS,
 	)
 
@@ -119,3 +143,307 @@ func (pg *Postgres) FindClientByUsername(username string) (client ClientData, er
 	}).Debug("Client found successfully")
 	return
 }
+
+// FindClientByIDIncludingClosed busca o cliente mesmo com a conta encerrada,
+// para exportação dos dados.
+func (pg *Postgres) FindClientByIDIncludingClosed(ctx context.Context, clientID string) (client ClientData, err error) {
+	q := fmt.Sprintf(
+		`SELECT %s
+		FROM %s
+		WHERE guid = $1`,
+		clientColumns,
+		DB_TABLE_CLIENTS,
+	)
+
+	err = pg.db.GetContext(ctx, &client, q, clientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errs.ErrNotFound
+			return
+		}
+		logger.Errorf("Failed to find client: %v", err)
+	}
+	return
+}
+
+// FindClosedClientByUsername busca uma conta encerrada, usada apenas para
+// informar o motivo da recusa no login.
+func (pg *Postgres) FindClosedClientByUsername(ctx context.Context, username string) (client ClientData, err error) {
+	q := fmt.Sprintf(
+		`SELECT %s
+		FROM %s
+		WHERE LOWER(username) = LOWER($1) and deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT 1`,
+		clientColumns,
+		DB_TABLE_CLIENTS,
+	)
+
+	err = pg.db.GetContext(ctx, &client, q, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errs.ErrNotFound
+			return
+		}
+		logger.Errorf("Failed to find closed client: %v", err)
+	}
+	return
+}
+
+func (pg *Postgres) UpdateClientTOTP(ctx context.Context, clientID string, secret *string, enabled bool) error {
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+		"enabled":  enabled,
+	}).Debug("Updating client TOTP")
+
+	query := fmt.Sprintf(
+		`UPDATE %s
+		SET totp_secret = $1, totp_enabled_at = CASE WHEN $2 THEN COALESCE(totp_enabled_at, NOW()) ELSE NULL END
+		WHERE guid = $3 and deleted_at IS NULL`,
+		DB_TABLE_CLIENTS,
+	)
+	_, err := pg.db.ExecContext(ctx, query, secret, enabled, clientID)
+	if err != nil {
+		logger.Errorf("Failed to update client TOTP: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (pg *Postgres) UpdateClientPassword(ctx context.Context, clientID, password string) error {
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Debug("Updating client password")
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET password = $1 WHERE guid = $2 and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, password, clientID)
+	if err != nil {
+		logger.Errorf("Failed to update client password: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Info("Client password updated successfully")
+	return nil
+}
+
+func (pg *Postgres) UpdateClientEmail(ctx context.Context, clientID, email string) error {
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Debug("Updating client email")
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET email = $1, email_verified_at = NULL WHERE guid = $2 and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, email, clientID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return errs.ErrEmailExists
+		}
+		logger.Errorf("Failed to update client email: %v", err)
+		return err
+	}
+	// conta encerrada ou anonimizada
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+// MarkClientEmailVerified só confirma se o e-mail ainda é o mesmo para o
+// qual o link foi enviado
+func (pg *Postgres) MarkClientEmailVerified(ctx context.Context, clientID, email string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET email_verified_at = NOW() WHERE guid = $1 and LOWER(email) = LOWER($2) and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, clientID, email)
+	if err != nil {
+		logger.Errorf("Failed to mark client email verified: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Info("Client email verified")
+	return nil
+}
+
+// likeEscaper escapa os curingas do LIKE para que o termo seja buscado literalmente
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchClients busca por trecho do username ou do e-mail, sem diferenciar
+// maiúsculas
+func (pg *Postgres) SearchClients(ctx context.Context, term string, limit, offset int) (clients []ClientData, err error) {
+	logger.WithFields(logrus.Fields{
+		"term": term,
+	}).Debug("Searching clients")
+
+	q := fmt.Sprintf(
+		`SELECT %s
+		FROM %s
+		WHERE deleted_at IS NULL and ($1 = '' or username ILIKE '%%' || $4 || '%%' ESCAPE '\' or email ILIKE '%%' || $4 || '%%' ESCAPE '\')
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		clientColumns,
+		DB_TABLE_CLIENTS,
+	)
+
+	clients = []ClientData{}
+	err = pg.db.SelectContext(ctx, &clients, q, term, limit, offset, likeEscaper.Replace(term))
+	if err != nil {
+		logger.Errorf("Failed to search clients: %v", err)
+	}
+	return
+}
+
+func (pg *Postgres) UpdateClientRole(ctx context.Context, clientID, role string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET role = $1 WHERE guid = $2 and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, role, clientID)
+	if err != nil {
+		logger.Errorf("Failed to update client role: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+		"role":     role,
+	}).Info("Client role updated")
+	return nil
+}
+
+func (pg *Postgres) SuspendClient(ctx context.Context, clientID string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET suspended_at = COALESCE(suspended_at, NOW()) WHERE guid = $1 and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, clientID)
+	if err != nil {
+		logger.Errorf("Failed to suspend client: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Info("Client suspended")
+	return nil
+}
+
+func (pg *Postgres) UnsuspendClient(ctx context.Context, clientID string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET suspended_at = NULL WHERE guid = $1 and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, clientID)
+	if err != nil {
+		logger.Errorf("Failed to unsuspend client: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Info("Client unsuspended")
+	return nil
+}
+
+func (pg *Postgres) CloseClient(ctx context.Context, clientID string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET deleted_at = NOW() WHERE guid = $1 and deleted_at IS NULL",
+		DB_TABLE_CLIENTS,
+	)
+	res, err := pg.db.ExecContext(ctx, query, clientID)
+	if err != nil {
+		logger.Errorf("Failed to close client: %v", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Info("Client account closed")
+	return nil
+}
+
+// EraseClient anonimiza os dados pessoais do cliente e encerra a conta. Apostas,
+// carteira e ledger são mantidos por exigência legal. Retorna o username
+// anterior para que o cache possa ser limpo.
+func (pg *Postgres) EraseClient(ctx context.Context, clientID, username, password string) (previous string, err error) {
+	tx, err := pg.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.Errorf("Failed to begin transaction: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	q := fmt.Sprintf("SELECT username FROM %s WHERE guid = $1 FOR UPDATE", DB_TABLE_CLIENTS)
+	err = tx.GetContext(ctx, &previous, q, clientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errs.ErrNotFound
+		return
+	} else if err != nil {
+		logger.Errorf("Failed to lock client: %v", err)
+		return
+	}
+
+	q = fmt.Sprintf(
+		`UPDATE %s SET username = $1, password = $2, email = NULL, email_verified_at = NULL,
+		totp_secret = NULL, totp_enabled_at = NULL, deleted_at = COALESCE(deleted_at, NOW())
+		WHERE guid = $3`,
+		DB_TABLE_CLIENTS,
+	)
+	if _, err = tx.ExecContext(ctx, q, username, password, clientID); err != nil {
+		logger.Errorf("Failed to anonymise client: %v", err)
+		return
+	}
+
+	q = fmt.Sprintf("DELETE FROM %s WHERE client_id = $1", DB_TABLE_RECOVERY_CODES)
+	if _, err = tx.ExecContext(ctx, q, clientID); err != nil {
+		logger.Errorf("Failed to delete recovery codes: %v", err)
+		return
+	}
+
+	// inclui tentativas anteriores ao vínculo com o client_id (username errado, etc.)
+	q = fmt.Sprintf(
+		"UPDATE %s SET username = $1, ip = '', user_agent = '' WHERE client_id = $2 or LOWER(username) = LOWER($3)",
+		DB_TABLE_LOGIN_ATTEMPTS,
+	)
+	if _, err = tx.ExecContext(ctx, q, username, clientID, previous); err != nil {
+		logger.Errorf("Failed to anonymise login attempts: %v", err)
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Info("Client personal data erased")
+	return
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+type LedgerEntryData struct {
+	GUID         string  `db:"guid" json:"guid"`
+	ClientID     string  `db:"client_id" json:"client_id"`
+	EntryType    string  `db:"entry_type" json:"entry_type"`
+	Amount       float64 `db:"amount" json:"amount"`
+	BalanceAfter float64 `db:"balance_after" json:"balance_after"`
+	ReferenceID  string  `db:"reference_id" json:"reference_id"`
+	Reason       *string `db:"reason" json:"reason"`
+	CreatedBy    *string `db:"created_by" json:"created_by"`
+	ApprovedBy   *string `db:"approved_by" json:"approved_by"`
+	CreatedAt    string  `db:"created_at" json:"created_at"`
+}
+
+func insertLedgerEntry(ctx context.Context, tx *sqlx.Tx, e LedgerEntryData) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (guid, client_id, entry_type, amount, balance_after, reference_id, reason, created_by, approved_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		DB_TABLE_WALLET_LEDGER,
+	)
+	_, err := tx.ExecContext(ctx, query,
+		e.GUID,
+		e.ClientID,
+		e.EntryType,
+		e.Amount,
+		e.BalanceAfter,
+		e.ReferenceID,
+		e.Reason,
+		e.CreatedBy,
+		e.ApprovedBy,
+	)
+	if err != nil {
+		logger.Errorf("Failed to insert ledger entry: %v", err)
+	}
+	return err
+}
+
+func (pg *Postgres) FindLedgerEntriesByClientID(ctx context.Context, clientID string, limit, offset int) (entries []LedgerEntryData, err error) {
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Debug("Searching ledger entries by client ID")
+
+	q := fmt.Sprintf(
+		`SELECT guid, client_id, entry_type, amount, balance_after, reference_id, reason, created_by, approved_by, created_at
+		FROM %s
+		WHERE client_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		DB_TABLE_WALLET_LEDGER,
+	)
+
+	entries = []LedgerEntryData{}
+	err = pg.db.SelectContext(ctx, &entries, q, clientID, limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to find ledger entries: %v", err)
+	}
+	return
+}
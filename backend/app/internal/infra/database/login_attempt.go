@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+type LoginAttemptData struct {
+	GUID      string  `db:"guid" json:"guid"`
+	Username  string  `db:"username" json:"username"`
+	ClientID  *string `db:"client_id" json:"client_id"`
+	IP        string  `db:"ip" json:"ip"`
+	UserAgent string  `db:"user_agent" json:"user_agent"`
+	Reason    string  `db:"reason" json:"reason"`
+	CreatedAt string  `db:"created_at" json:"created_at"`
+}
+
+func (pg *Postgres) InsertLoginAttempt(ctx context.Context, a LoginAttemptData) error {
+	logger.WithFields(logrus.Fields{
+		"username": a.Username,
+		"ip":       a.IP,
+		"reason":   a.Reason,
+	}).Debug("Inserting login attempt")
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (guid, username, client_id, ip, user_agent, reason) VALUES ($1, $2, $3, $4, $5, $6)",
+		DB_TABLE_LOGIN_ATTEMPTS,
+	)
+	_, err := pg.db.ExecContext(ctx, query, a.GUID, a.Username, a.ClientID, a.IP, a.UserAgent, a.Reason)
+	if err != nil {
+		logger.Errorf("Failed to insert login attempt: %v", err)
+		return err
+	}
+	return nil
+}
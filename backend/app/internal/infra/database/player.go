@@ -6,6 +6,7 @@ type PlayerData struct {
 	ClientID string  `json:"client_id"`
 	Balance  float64 `json:"balance"`
 	InPlay   bool    `json:"in_play"`
+	MatchID  string  `json:"match_id,omitempty"`
 }
 
 func (p *PlayerData) MarshalBinary() ([]byte, error) {
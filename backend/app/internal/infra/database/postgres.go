@@ -10,6 +10,13 @@ import (
 const (
 	DB_TABLE_CLIENTS = "clients"
 	DB_TABLE_WALLETS = "wallets"
+
+	DB_TABLE_LOGIN_ATTEMPTS = "login_attempts"
+	DB_TABLE_RECOVERY_CODES = "recovery_codes"
+	DB_TABLE_BETS           = "bets"
+	DB_TABLE_WALLET_LEDGER  = "wallet_ledger"
+	DB_TABLE_ADJUSTMENTS    = "balance_adjustments"
+	DB_TABLE_API_KEYS       = "api_keys"
 )
 
 type Postgres struct {
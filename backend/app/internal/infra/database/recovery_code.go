@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+func (pg *Postgres) ReplaceRecoveryCodes(ctx context.Context, clientID string, hashes []string) error {
+	logger.WithFields(logrus.Fields{
+		"clientID": clientID,
+	}).Debug("Replacing recovery codes")
+
+	tx, err := pg.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.Errorf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE client_id = $1", DB_TABLE_RECOVERY_CODES), clientID)
+	if err != nil {
+		logger.Errorf("Failed to delete recovery codes: %v", err)
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (guid, client_id, code_hash) VALUES ($1, $2, $3)", DB_TABLE_RECOVERY_CODES)
+	for _, hash := range hashes {
+		_, err = tx.ExecContext(ctx, query, uuid.New().String(), clientID, hash)
+		if err != nil {
+			logger.Errorf("Failed to insert recovery code: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (pg *Postgres) DeleteRecoveryCodes(ctx context.Context, clientID string) error {
+	_, err := pg.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE client_id = $1", DB_TABLE_RECOVERY_CODES), clientID)
+	if err != nil {
+		logger.Errorf("Failed to delete recovery codes: %v", err)
+	}
+	return err
+}
+
+// UseRecoveryCode marca o código como usado e retorna false se ele não existe
+// ou já foi consumido.
+func (pg *Postgres) UseRecoveryCode(ctx context.Context, clientID, hash string) (bool, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET used_at = NOW() WHERE client_id = $1 and code_hash = $2 and used_at IS NULL",
+		DB_TABLE_RECOVERY_CODES,
+	)
+	res, err := pg.db.ExecContext(ctx, query, clientID, hash)
+	if err != nil {
+		logger.Errorf("Failed to use recovery code: %v", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -77,6 +77,18 @@ func (r *Redis) Get(ctx context.Context, key string, value interface{}) error {
 	return nil
 }
 
+// Take lê e remove a chave atomicamente, para valores de uso único
+func (r *Redis) Take(ctx context.Context, key string, value interface{}) error {
+	data, err := r.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			logger.Errorf("Failed to take Redis key: %v", err)
+		}
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
 func (r *Redis) Delete(ctx context.Context, key string) error {
 	logger.WithFields(logrus.Fields{
 		"key": key,
@@ -94,6 +106,67 @@ func (r *Redis) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (r *Redis) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	logger.WithFields(logrus.Fields{
+		"key": key,
+		"ttl": ttl,
+	}).Debug("Setting Redis key with TTL")
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		logger.Errorf("Failed to marshal value: %v", err)
+		return err
+	}
+
+	err = r.client.Set(ctx, key, data, ttl).Err()
+	if err != nil {
+		logger.Errorf("Failed to set Redis key: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Redis) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		logger.Errorf("Failed to marshal value: %v", err)
+		return false, err
+	}
+
+	ok, err := r.client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		logger.Errorf("Failed to set Redis key: %v", err)
+		return false, err
+	}
+	return ok, nil
+}
+
+// Increment incrementa um contador e aplica o TTL apenas na criação da chave,
+// de forma que a janela não seja renovada a cada incremento.
+func (r *Redis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		logger.Errorf("Failed to increment Redis key: %v", err)
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		logger.Errorf("Failed to get Redis key TTL: %v", err)
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
 func (r *Redis) Close() error {
 	logger.Debug("Closing Redis connection")
 	err := r.client.Close()
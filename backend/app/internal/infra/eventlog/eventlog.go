@@ -0,0 +1,262 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix = "events:"
+
+	fieldMessage  = "message"
+	fieldSessions = "sessions"
+	fieldClose    = "close"
+
+	readCount = 100
+
+	// ID anterior a qualquer evento
+	noEvents = "0-0"
+)
+
+var (
+	ErrBusy      = errors.New("too many event streams")
+	ErrInvalidID = errors.New("invalid event ID")
+)
+
+type Config struct {
+	// eventos mantidos por cliente
+	Size int64
+	// o buffer de um cliente expira depois desse tempo sem eventos
+	TTL time.Duration
+	// leituras bloqueantes simultâneas; cada uma ocupa uma conexão do Redis
+	MaxFollowers int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Size:         100,
+		TTL:          10 * time.Minute,
+		MaxFollowers: 1000,
+	}
+}
+
+// Entry é um evento enviado a um cliente. ID é o ID do Redis Stream
+// (<ms>-<seq>), crescente por cliente, usado para retomar a leitura.
+type Entry struct {
+	ID      string
+	Message []byte
+	// sessões a que o evento se destina; nil entrega a todas
+	Sessions []string
+	// a conexão da sessão é encerrada depois do evento
+	Close bool
+}
+
+// ForSession indica se o evento deve ser entregue a uma conexão identificada
+// pelas sessões informadas; conexões sem sessão (chave de API) só recebem
+// eventos sem destinatário específico.
+func (e Entry) ForSession(sessions ...string) bool {
+	if e.Sessions == nil {
+		return true
+	}
+	for _, s := range e.Sessions {
+		for _, session := range sessions {
+			if s == session && s != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Log guarda os eventos recentes de cada cliente em um Redis Stream
+// (events:<client_id>) limitado a Config.Size entradas, para que conexões
+// que caíram possam retomar do último evento recebido.
+type Log struct {
+	client    *redis.Client
+	config    Config
+	followers chan struct{}
+}
+
+// New recebe um cliente Redis próprio: as leituras bloqueantes de Follow não
+// devem disputar o pool com o restante da aplicação.
+func New(client *redis.Client, config Config) *Log {
+	return &Log{
+		client:    client,
+		config:    config,
+		followers: make(chan struct{}, config.MaxFollowers),
+	}
+}
+
+func (l *Log) Append(ctx context.Context, clientID string, e Entry) (string, error) {
+	values := map[string]interface{}{
+		fieldMessage: e.Message,
+	}
+	if e.Sessions != nil {
+		values[fieldSessions] = strings.Join(e.Sessions, ",")
+	}
+	if e.Close {
+		values[fieldClose] = "1"
+	}
+
+	key := keyPrefix + clientID
+	pipe := l.client.TxPipeline()
+	add := pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: l.config.Size,
+		Values: values,
+	})
+	pipe.Expire(ctx, key, l.config.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+	return add.Val(), nil
+}
+
+// Tail retorna o ID do evento mais recente do cliente, ou "0-0" se não houver
+func (l *Log) Tail(ctx context.Context, clientID string) (string, error) {
+	msgs, err := l.client.XRevRangeN(ctx, keyPrefix+clientID, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return noEvents, nil
+	}
+	return msgs[0].ID, nil
+}
+
+// Missed indica se algum evento posterior a lastID já foi descartado do
+// buffer (ou o buffer inteiro expirou), caso em que o cliente precisa
+// recarregar o estado em vez de apenas retomar.
+func (l *Log) Missed(ctx context.Context, clientID, lastID string) (bool, error) {
+	if _, _, err := parseID(lastID); err != nil {
+		return false, err
+	}
+
+	key := keyPrefix + clientID
+	exists, err := l.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if exists == 0 {
+		// sem buffer, só quem já tinha recebido algum evento pode ter perdido
+		// outros
+		return lastID != noEvents, nil
+	}
+
+	info, err := l.client.XInfoStream(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if info.MaxDeletedEntryID == "" {
+		return false, nil
+	}
+	return compareIDs(lastID, info.MaxDeletedEntryID) < 0, nil
+}
+
+// Cursor lê os eventos de um cliente em ordem, a partir de um ID
+type Cursor struct {
+	log      *Log
+	clientID string
+	lastID   string
+	once     sync.Once
+}
+
+// Follow reserva uma das leituras bloqueantes; retorna ErrBusy se todas
+// estiverem em uso. O cursor deve ser fechado com Close.
+func (l *Log) Follow(clientID, lastID string) (*Cursor, error) {
+	if _, _, err := parseID(lastID); err != nil {
+		return nil, err
+	}
+	select {
+	case l.followers <- struct{}{}:
+	default:
+		return nil, ErrBusy
+	}
+	return &Cursor{log: l, clientID: clientID, lastID: lastID}, nil
+}
+
+// Next retorna os eventos posteriores ao último lido, esperando até block
+// por novos eventos; nenhum evento e erro nil indica que o tempo acabou.
+func (c *Cursor) Next(ctx context.Context, block time.Duration) ([]Entry, error) {
+	streams, err := c.log.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{keyPrefix + c.clientID, c.lastID},
+		Count:   readCount,
+		Block:   block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			entries = append(entries, entryFrom(msg))
+			c.lastID = msg.ID
+		}
+	}
+	return entries, nil
+}
+
+func (c *Cursor) LastID() string {
+	return c.lastID
+}
+
+func (c *Cursor) Close() {
+	c.once.Do(func() {
+		<-c.log.followers
+	})
+}
+
+func entryFrom(msg redis.XMessage) Entry {
+	e := Entry{ID: msg.ID}
+	if m, ok := msg.Values[fieldMessage].(string); ok {
+		e.Message = []byte(m)
+	}
+	if s, ok := msg.Values[fieldSessions].(string); ok {
+		e.Sessions = strings.Split(s, ",")
+	}
+	e.Close = msg.Values[fieldClose] == "1"
+	return e
+}
+
+func parseID(id string) (ms, seq uint64, err error) {
+	msPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	if ms, err = strconv.ParseUint(msPart, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	if seq, err = strconv.ParseUint(seqPart, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return ms, seq, nil
+}
+
+func compareIDs(a, b string) int {
+	aMs, aSeq, _ := parseID(a)
+	bMs, bSeq, _ := parseID(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
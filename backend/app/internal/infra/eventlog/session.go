@@ -0,0 +1,141 @@
+package eventlog
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	sessionKeyPrefix = "ws:resume:"
+
+	fieldClient  = "client"
+	fieldPending = "pending"
+
+	tokenBytes = 32
+
+	pendingPoll = 100 * time.Millisecond
+)
+
+var ErrSessionNotFound = errors.New("resume session not found or expired")
+
+// Session identifica uma conexão WebSocket no buffer do cliente: as respostas
+// enviadas a ela são gravadas com Tag, para que uma nova conexão que apresente
+// Token possa recebê-las depois de uma queda.
+type Session struct {
+	ClientID string
+	// entregue ao cliente; só o hash é guardado
+	Token string
+	Tag   string
+}
+
+// NewSession cria uma sessão que expira junto com o buffer do cliente
+func (l *Log) NewSession(ctx context.Context, clientID string) (Session, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return Session{}, err
+	}
+	s := Session{ClientID: clientID, Token: hex.EncodeToString(buf)}
+	s.Tag = sessionTag(s.Token)
+
+	key := sessionKeyPrefix + s.Tag
+	pipe := l.client.TxPipeline()
+	pipe.HSet(ctx, key, fieldClient, clientID, fieldPending, 0)
+	pipe.Expire(ctx, key, l.config.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Session{}, err
+	}
+	return s, nil
+}
+
+// ResumeSession recupera a sessão do token, que só pode ser retomada pelo
+// mesmo cliente
+func (l *Log) ResumeSession(ctx context.Context, clientID, token string) (Session, error) {
+	s := Session{ClientID: clientID, Token: token, Tag: sessionTag(token)}
+	owner, err := l.client.HGet(ctx, sessionKeyPrefix+s.Tag, fieldClient).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && owner != clientID) {
+		return Session{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return Session{}, err
+	}
+	return s, l.client.Expire(ctx, sessionKeyPrefix+s.Tag, l.config.TTL).Err()
+}
+
+// Begin marca uma requisição da sessão em andamento; deve ser seguido de End
+// depois que a resposta for gravada
+func (l *Log) Begin(ctx context.Context, s Session) error {
+	key := sessionKeyPrefix + s.Tag
+	pipe := l.client.TxPipeline()
+	pipe.HIncrBy(ctx, key, fieldPending, 1)
+	pipe.Expire(ctx, key, l.config.TTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (l *Log) End(ctx context.Context, s Session) error {
+	return l.client.HIncrBy(ctx, sessionKeyPrefix+s.Tag, fieldPending, -1).Err()
+}
+
+// WaitIdle espera até timeout que as requisições em andamento da sessão, que
+// podem estar em outra instância, gravem suas respostas. Retorna quantas
+// ainda não terminaram.
+func (l *Log) WaitIdle(ctx context.Context, s Session, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pendingPoll)
+	defer ticker.Stop()
+	pending := 0
+	for {
+		n, err := l.client.HGet(ctx, sessionKeyPrefix+s.Tag, fieldPending).Int()
+		switch {
+		case errors.Is(err, redis.Nil):
+			return 0, nil
+		case err != nil && ctx.Err() != nil:
+			return pending, nil
+		case err != nil:
+			return 0, err
+		case n <= 0:
+			return 0, nil
+		}
+		pending = n
+
+		select {
+		case <-ctx.Done():
+			return pending, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Since retorna os eventos posteriores a lastID ainda no buffer, em ordem
+func (l *Log) Since(ctx context.Context, clientID, lastID string) ([]Entry, error) {
+	if _, _, err := parseID(lastID); err != nil {
+		return nil, err
+	}
+	msgs, err := l.client.XRangeN(ctx, keyPrefix+clientID, "("+lastID, "+", l.config.Size).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(msgs))
+	for _, msg := range msgs {
+		entries = append(entries, entryFrom(msg))
+	}
+	return entries, nil
+}
+
+// After indica se o evento a é posterior ao evento b
+func After(a, b string) bool {
+	return compareIDs(a, b) > 0
+}
+
+func sessionTag(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,252 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+const (
+	presenceKeyPrefix  = "ws:presence:"
+	instanceKeyPrefix  = "ws:instance:"
+	instanceChanPrefix = "ws:deliver:"
+	broadcastChan      = "ws:broadcast"
+
+	heartbeatPeriod = 10 * time.Second
+	heartbeatTTL    = 3 * heartbeatPeriod
+	presenceTimeout = 2 * time.Second
+
+	kindSend         = "send"
+	kindClose        = "close"
+	kindCloseAPIKeys = "close_api_keys"
+	kindBroadcast    = "broadcast"
+)
+
+// decrementa a contagem de conexões da instância e remove o campo ao zerar
+var disconnectScript = redis.NewScript(`
+local n = redis.call('HINCRBY', KEYS[1], ARGV[1], -1)
+if n <= 0 then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+type envelope struct {
+	Kind string `json:"kind"`
+	// instância que publicou; usada para ignorar a própria difusão
+	Origin   string   `json:"origin,omitempty"`
+	ClientID string   `json:"client_id,omitempty"`
+	Group    string   `json:"group,omitempty"`
+	Sessions []string `json:"sessions,omitempty"`
+	APIKeys  []string `json:"api_keys,omitempty"`
+	Message  []byte   `json:"message,omitempty"`
+	Code     int      `json:"code,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// Backplane permite que qualquer réplica da API entregue mensagens a
+// conexões abertas em outra réplica. Cada instância assina o próprio canal
+// no Redis pub/sub e registra em ws:presence:<client_id> quantas conexões
+// mantém de cada cliente; o envio só é publicado nas instâncias que têm o
+// cliente e ainda estão vivas (heartbeat).
+type Backplane struct {
+	client     *redis.Client
+	hub        *Hub
+	instanceID string
+}
+
+func NewBackplane(client *redis.Client, hub *Hub, instanceID string) *Backplane {
+	b := &Backplane{
+		client:     client,
+		hub:        hub,
+		instanceID: instanceID,
+	}
+	hub.TrackPresence(b)
+	return b
+}
+
+func (b *Backplane) SendToClient(ctx context.Context, clientID string, msg []byte) error {
+	return b.route(ctx, envelope{
+		Kind:     kindSend,
+		ClientID: clientID,
+		Message:  msg,
+	})
+}
+
+func (b *Backplane) CloseSessions(ctx context.Context, clientID string, sessions []string, msg []byte, code int, reason string) error {
+	return b.route(ctx, envelope{
+		Kind:     kindClose,
+		ClientID: clientID,
+		Sessions: sessions,
+		Message:  msg,
+		Code:     code,
+		Reason:   reason,
+	})
+}
+
+func (b *Backplane) CloseAPIKeys(ctx context.Context, clientID string, keys []string, msg []byte, code int, reason string) error {
+	return b.route(ctx, envelope{
+		Kind:     kindCloseAPIKeys,
+		ClientID: clientID,
+		APIKeys:  keys,
+		Message:  msg,
+		Code:     code,
+		Reason:   reason,
+	})
+}
+
+// Broadcast entrega direto às conexões do grupo nesta instância e publica no
+// canal de difusão, assinado por todas as instâncias; a presença não é
+// consultada, já que um grupo reúne vários clientes.
+func (b *Backplane) Broadcast(ctx context.Context, group string, msg []byte) error {
+	e := envelope{
+		Kind:    kindBroadcast,
+		Origin:  b.instanceID,
+		Group:   group,
+		Message: msg,
+	}
+	b.deliver(e)
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, broadcastChan, data).Err()
+}
+
+// Instances retorna as outras instâncias vivas que mantêm conexões do
+// cliente
+func (b *Backplane) Instances(ctx context.Context, clientID string) ([]string, error) {
+	counts, err := b.client.HGetAll(ctx, presenceKeyPrefix+clientID).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]string, 0, len(counts))
+	for instance := range counts {
+		if instance == b.instanceID {
+			continue
+		}
+		alive, err := b.client.Exists(ctx, instanceKeyPrefix+instance).Result()
+		if err != nil {
+			return nil, err
+		}
+		if alive == 0 {
+			// a instância caiu sem limpar a presença
+			b.client.HDel(ctx, presenceKeyPrefix+clientID, instance)
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// route entrega direto às conexões desta instância, sem depender da
+// presença no Redis (que pode estar atrasada ou indisponível), e publica
+// apenas para as outras instâncias que têm o cliente.
+func (b *Backplane) route(ctx context.Context, e envelope) error {
+	b.deliver(e)
+
+	instances, err := b.Instances(ctx, e.ClientID)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	for _, instance := range instances {
+		if data == nil {
+			if data, err = json.Marshal(e); err != nil {
+				return err
+			}
+		}
+		if err := b.client.Publish(ctx, instanceChanPrefix+instance, data).Err(); err != nil {
+			logger.WithFields(logrus.Fields{
+				"client_id": e.ClientID,
+				"instance":  instance,
+			}).Errorf("Failed to publish WebSocket message: %v", err)
+		}
+	}
+	return nil
+}
+
+func (b *Backplane) deliver(e envelope) {
+	switch {
+	case e.Kind == kindClose:
+		b.hub.closeLocal(e.ClientID, e.Sessions, e.Message, e.Code, e.Reason)
+	case e.Kind == kindCloseAPIKeys:
+		b.hub.closeAPIKeysLocal(e.ClientID, e.APIKeys, e.Message, e.Code, e.Reason)
+	case e.Kind == kindBroadcast:
+		b.hub.broadcastLocal(e.Group, e.Message)
+	default:
+		b.hub.sendLocal(e.ClientID, e.Message)
+	}
+}
+
+func (b *Backplane) Connected(clientID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+	if err := b.client.HIncrBy(ctx, presenceKeyPrefix+clientID, b.instanceID, 1).Err(); err != nil {
+		logger.Errorf("Failed to register WebSocket presence: %v", err)
+	}
+}
+
+func (b *Backplane) Disconnected(clientID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+	err := disconnectScript.Run(ctx, b.client, []string{presenceKeyPrefix + clientID}, b.instanceID).Err()
+	if err != nil {
+		logger.Errorf("Failed to clear WebSocket presence: %v", err)
+	}
+}
+
+// Run mantém o heartbeat da instância e entrega as mensagens publicadas por
+// outras instâncias até o ctx ser cancelado.
+func (b *Backplane) Run(ctx context.Context) {
+	b.heartbeat(ctx)
+	sub := b.client.Subscribe(ctx, instanceChanPrefix+b.instanceID, broadcastChan)
+	defer sub.Close()
+
+	logger.WithFields(logrus.Fields{
+		"instance": b.instanceID,
+	}).Info("WebSocket backplane started")
+
+	ticker := time.NewTicker(heartbeatPeriod)
+	defer ticker.Stop()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			b.client.Del(context.Background(), instanceKeyPrefix+b.instanceID)
+			return
+		case <-ticker.C:
+			b.heartbeat(ctx)
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			var e envelope
+			if err := json.Unmarshal([]byte(m.Payload), &e); err != nil {
+				logger.Errorf("Invalid backplane message: %v", err)
+				continue
+			}
+			if e.Origin == b.instanceID {
+				// já entregue localmente por Broadcast
+				continue
+			}
+			b.deliver(e)
+		}
+	}
+}
+
+func (b *Backplane) heartbeat(ctx context.Context) {
+	err := b.client.Set(ctx, instanceKeyPrefix+b.instanceID, time.Now().Unix(), heartbeatTTL).Err()
+	if err != nil {
+		logger.Errorf("Failed to refresh instance heartbeat: %v", err)
+	}
+}
@@ -0,0 +1,480 @@
+package hub
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+type Config struct {
+	// mensagens pendentes por conexão antes de ela ser considerada lenta
+	SendBuffer int
+	PingPeriod time.Duration
+	PongWait   time.Duration
+	WriteWait  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		SendBuffer: 256,
+		PingPeriod: 30 * time.Second,
+		PongWait:   60 * time.Second,
+		WriteWait:  10 * time.Second,
+	}
+}
+
+// Router entrega mensagens a clientes conectados. O Hub entrega apenas às
+// conexões desta instância; o Backplane, a qualquer instância.
+type Router interface {
+	SendToClient(ctx context.Context, clientID string, msg []byte) error
+	// CloseSessions envia msg e fecha as conexões das sessões informadas
+	// (nil fecha todas as conexões do cliente)
+	CloseSessions(ctx context.Context, clientID string, sessions []string, msg []byte, code int, reason string) error
+	// CloseAPIKeys envia msg e fecha as conexões autenticadas pelas chaves
+	// de API informadas
+	CloseAPIKeys(ctx context.Context, clientID string, keys []string, msg []byte, code int, reason string) error
+	// Broadcast entrega msg às conexões que entraram no grupo (uma mesa, por
+	// exemplo) com Join; o grupo vazio alcança todas as conexões
+	Broadcast(ctx context.Context, group string, msg []byte) error
+}
+
+// PresenceTracker é avisado quando um cliente abre ou fecha uma conexão
+// nesta instância.
+type PresenceTracker interface {
+	Connected(clientID string)
+	Disconnected(clientID string)
+}
+
+// Hub mantém as conexões WebSocket abertas por cliente. É seguro para uso
+// concorrente, então qualquer parte do serviço pode enviar mensagens para um
+// cliente sem conhecer suas conexões.
+type Hub struct {
+	config   Config
+	mu       sync.RWMutex
+	conns    map[string]map[*Conn]struct{}
+	groups   map[string]map[*Conn]struct{}
+	presence PresenceTracker
+}
+
+func New(config Config) *Hub {
+	return &Hub{
+		config: config,
+		conns:  make(map[string]map[*Conn]struct{}),
+		groups: make(map[string]map[*Conn]struct{}),
+	}
+}
+
+func (h *Hub) TrackPresence(p PresenceTracker) {
+	h.presence = p
+}
+
+// Register passa a conexão para o hub, que inicia o goroutine de escrita.
+// Depois disso a conexão só deve ser escrita através de Send. Apenas um de
+// sessionToken e apiKeyID é preenchido, conforme a autenticação da conexão.
+func (h *Hub) Register(clientID, sessionToken, apiKeyID string, ws *websocket.Conn) *Conn {
+	c := &Conn{
+		ClientID: clientID,
+		Session:  sessionToken,
+		APIKey:   apiKeyID,
+		ws:       ws,
+		hub:      h,
+		send:     make(chan frame, h.config.SendBuffer),
+		done:     make(chan struct{}),
+	}
+
+	ws.SetReadDeadline(time.Now().Add(h.config.PongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(h.config.PongWait))
+	})
+
+	h.mu.Lock()
+	if h.conns[clientID] == nil {
+		h.conns[clientID] = make(map[*Conn]struct{})
+	}
+	h.conns[clientID][c] = struct{}{}
+	total := len(h.conns[clientID])
+	h.mu.Unlock()
+
+	logger.WithFields(logrus.Fields{
+		"client_id":   clientID,
+		"connections": total,
+	}).Info("WebSocket connection registered")
+
+	// síncrono, para que o aviso de conexão sempre preceda o de desconexão
+	if h.presence != nil {
+		h.presence.Connected(clientID)
+	}
+
+	go c.writePump()
+	return c
+}
+
+func (h *Hub) unregister(c *Conn) {
+	h.mu.Lock()
+	conns, ok := h.conns[c.ClientID]
+	if ok {
+		delete(conns, c)
+		if len(conns) == 0 {
+			delete(h.conns, c.ClientID)
+		}
+	}
+	for group := range c.groups {
+		h.leave(c, group)
+	}
+	h.mu.Unlock()
+
+	// Close pode ser chamado por quem está publicando; não bloqueia no Redis
+	if ok && h.presence != nil {
+		go h.presence.Disconnected(c.ClientID)
+	}
+}
+
+func (h *Hub) Connections(clientID string) []*Conn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	conns := make([]*Conn, 0, len(h.conns[clientID]))
+	for c := range h.conns[clientID] {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// SendToClient entrega a mensagem a todas as conexões locais do cliente
+func (h *Hub) SendToClient(ctx context.Context, clientID string, msg []byte) error {
+	h.sendLocal(clientID, msg)
+	return nil
+}
+
+func (h *Hub) CloseSessions(ctx context.Context, clientID string, sessions []string, msg []byte, code int, reason string) error {
+	h.closeLocal(clientID, sessions, msg, code, reason)
+	return nil
+}
+
+func (h *Hub) CloseAPIKeys(ctx context.Context, clientID string, keys []string, msg []byte, code int, reason string) error {
+	h.closeAPIKeysLocal(clientID, keys, msg, code, reason)
+	return nil
+}
+
+func (h *Hub) Broadcast(ctx context.Context, group string, msg []byte) error {
+	h.broadcastLocal(group, msg)
+	return nil
+}
+
+// Join inclui a conexão no grupo; a conexão sai de todos os grupos ao ser
+// fechada
+func (h *Hub) Join(c *Conn, group string) {
+	if group == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	if h.groups[group] == nil {
+		h.groups[group] = make(map[*Conn]struct{})
+	}
+	h.groups[group][c] = struct{}{}
+	if c.groups == nil {
+		c.groups = make(map[string]struct{})
+	}
+	c.groups[group] = struct{}{}
+}
+
+func (h *Hub) Leave(c *Conn, group string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.leave(c, group)
+}
+
+// leave exige h.mu
+func (h *Hub) leave(c *Conn, group string) {
+	delete(c.groups, group)
+	members, ok := h.groups[group]
+	if !ok {
+		return
+	}
+	delete(members, c)
+	if len(members) == 0 {
+		delete(h.groups, group)
+	}
+}
+
+func (h *Hub) sendLocal(clientID string, msg []byte) int {
+	sent := 0
+	for _, c := range h.Connections(clientID) {
+		if c.Send(msg) {
+			sent++
+		}
+	}
+	return sent
+}
+
+func (h *Hub) closeLocal(clientID string, sessions []string, msg []byte, code int, reason string) {
+	only := set(sessions)
+	h.closeMatching(clientID, func(c *Conn) bool {
+		// conexões por chave de API não pertencem a nenhuma sessão
+		return sessions == nil || (c.Session != "" && only[c.Session])
+	}, msg, code, reason)
+}
+
+func (h *Hub) closeAPIKeysLocal(clientID string, keys []string, msg []byte, code int, reason string) {
+	only := set(keys)
+	h.closeMatching(clientID, func(c *Conn) bool {
+		return c.APIKey != "" && only[c.APIKey]
+	}, msg, code, reason)
+}
+
+func (h *Hub) closeMatching(clientID string, match func(*Conn) bool, msg []byte, code int, reason string) {
+	for _, c := range h.Connections(clientID) {
+		if !match(c) {
+			continue
+		}
+		if msg != nil {
+			c.Send(msg)
+		}
+		c.Close(code, reason)
+	}
+}
+
+func set(values []string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, v := range values {
+		s[v] = true
+	}
+	return s
+}
+
+func (h *Hub) broadcastLocal(group string, msg []byte) {
+	for _, c := range h.members(group) {
+		c.Send(msg)
+	}
+}
+
+func (h *Hub) members(group string) []*Conn {
+	if group == "" {
+		return h.all()
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	members := make([]*Conn, 0, len(h.groups[group]))
+	for c := range h.groups[group] {
+		members = append(members, c)
+	}
+	return members
+}
+
+func (h *Hub) all() []*Conn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var all []*Conn
+	for _, conns := range h.conns {
+		for c := range conns {
+			all = append(all, c)
+		}
+	}
+	return all
+}
+
+func (h *Hub) Connected(clientID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns[clientID]) > 0
+}
+
+// Shutdown fecha todas as conexões; usado no encerramento do servidor, já
+// que http.Server.Shutdown não acompanha conexões sequestradas pelo upgrade.
+func (h *Hub) Shutdown() {
+	for _, c := range h.all() {
+		c.Close(websocket.CloseGoingAway, "server shutting down")
+	}
+}
+
+// Encoding converte as mensagens enviadas pelo servidor, sempre em JSON,
+// para o formato negociado pela conexão.
+type Encoding struct {
+	Binary    bool
+	Transcode func(msg []byte) ([]byte, error)
+}
+
+type frame struct {
+	data []byte
+	// já está no formato da conexão
+	encoded bool
+	binary  bool
+}
+
+type Conn struct {
+	ClientID string
+	Session  string
+	// ID da chave de API que autenticou a conexão
+	APIKey string
+	// grupos em que a conexão entrou, protegidos por hub.mu
+	groups   map[string]struct{}
+	ws       *websocket.Conn
+	hub      *Hub
+	send     chan frame
+	encoding atomic.Pointer[Encoding]
+
+	closeOnce   sync.Once
+	done        chan struct{}
+	closeCode   int
+	closeReason string
+}
+
+// ReadMessage lê a próxima mensagem do cliente. Só o goroutine de leitura da
+// conexão deve chamá-lo.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	_, msg, err := c.ws.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	return msg, c.ws.SetReadDeadline(time.Now().Add(c.hub.config.PongWait))
+}
+
+// Subprotocol retorna o subprotocolo aceito no handshake
+func (c *Conn) Subprotocol() string {
+	return c.ws.Subprotocol()
+}
+
+// SetEncoding define como as mensagens do servidor são convertidas para
+// esta conexão; nil mantém o JSON.
+func (c *Conn) SetEncoding(e *Encoding) {
+	c.encoding.Store(e)
+}
+
+// Send enfileira uma mensagem do servidor em JSON, convertida para o formato
+// da conexão no goroutine de escrita.
+func (c *Conn) Send(msg []byte) bool {
+	return c.enqueue(frame{data: msg})
+}
+
+// SendEncoded enfileira uma mensagem já no formato da conexão
+func (c *Conn) SendEncoded(data []byte, binary bool) bool {
+	return c.enqueue(frame{data: data, encoded: true, binary: binary})
+}
+
+// enqueue não bloqueia. Se o buffer estiver cheio o cliente não está
+// acompanhando e a conexão é encerrada.
+func (c *Conn) enqueue(f frame) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- f:
+		return true
+	case <-c.done:
+		return false
+	default:
+		logger.WithFields(logrus.Fields{
+			"client_id": c.ClientID,
+			"buffer":    cap(c.send),
+		}).Warn("Slow WebSocket consumer, disconnecting")
+		c.Close(websocket.CloseTryAgainLater, "slow consumer")
+		return false
+	}
+}
+
+// Close remove a conexão do hub e pede ao goroutine de escrita que envie o
+// frame de fechamento. Pode ser chamado mais de uma vez.
+func (c *Conn) Close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+		c.hub.unregister(c)
+	})
+}
+
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// writePump é o único goroutine que escreve na conexão; serializa respostas,
+// mensagens do servidor e pings.
+func (c *Conn) writePump() {
+	ticker := time.NewTicker(c.hub.config.PingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+		logger.Infof("Closing WebSocket connection for client %s", c.ClientID)
+	}()
+
+	for {
+		select {
+		case f := <-c.send:
+			if err := c.write(f); err != nil {
+				logger.Errorf("Error writing message: %v", err)
+				c.Close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.hub.config.WriteWait)); err != nil {
+				logger.Errorf("Error sending ping: %v", err)
+				c.Close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				// um consumidor lento não receberia o backlog de qualquer forma
+				if c.closeCode != websocket.CloseTryAgainLater {
+					c.flush()
+				}
+				msg := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+				c.ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(c.hub.config.WriteWait))
+			}
+			return
+		}
+	}
+}
+
+// flush entrega o que já estava enfileirado antes do fechamento, como o aviso
+// de sessão revogada.
+func (c *Conn) flush() {
+	for {
+		select {
+		case f := <-c.send:
+			if err := c.write(f); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *Conn) write(f frame) error {
+	if !f.encoded {
+		if e := c.encoding.Load(); e != nil {
+			data, err := e.Transcode(f.data)
+			if err != nil {
+				// uma mensagem que não converte não derruba a conexão
+				logger.Errorf("Failed to transcode WebSocket message: %v", err)
+				return nil
+			}
+			f.data, f.binary = data, e.Binary
+		}
+	}
+
+	messageType := websocket.TextMessage
+	if f.binary {
+		messageType = websocket.BinaryMessage
+	}
+	c.ws.SetWriteDeadline(time.Now().Add(c.hub.config.WriteWait))
+	return c.ws.WriteMessage(messageType, f.data)
+}
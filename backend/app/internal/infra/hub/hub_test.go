@@ -0,0 +1,128 @@
+package hub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dial abre uma conexão real e a registra no hub, retornando também o lado
+// do cliente para ler o que o servidor enviou
+func dial(t *testing.T, h *Hub, clientID string) (*Conn, *websocket.Conn) {
+	t.Helper()
+	registered := make(chan *Conn, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		registered <- h.Register(clientID, "session-"+clientID, "", ws)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return <-registered, client
+}
+
+// marker é enviado depois da difusão: como a fila de cada conexão é
+// ordenada, recebê-lo primeiro significa que a difusão não chegou
+const marker = "marker"
+
+// received indica se a conexão recebeu msg antes do marcador
+func received(t *testing.T, c *Conn, ws *websocket.Conn, msg string) bool {
+	t.Helper()
+	c.Send([]byte(marker))
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, got, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatalf("reading from %s: %v", c.ClientID, err)
+	}
+	if string(got) == marker {
+		return false
+	}
+	if string(got) != msg {
+		t.Fatalf("%s received %q, want %q", c.ClientID, got, msg)
+	}
+	if _, got, err = ws.ReadMessage(); err != nil || string(got) != marker {
+		t.Fatalf("%s received %q, %v, want the marker", c.ClientID, got, err)
+	}
+	return true
+}
+
+func TestBroadcast(t *testing.T) {
+	h := New(DefaultConfig())
+	a, clientA := dial(t, h, "a")
+	b, clientB := dial(t, h, "b")
+	c, clientC := dial(t, h, "c")
+	h.Join(a, "table:1")
+	h.Join(b, "table:1")
+	h.Join(b, "table:2")
+
+	tests := []struct {
+		name  string
+		group string
+		// clientes que devem receber a mensagem
+		want map[string]bool
+	}{
+		{"group", "table:1", map[string]bool{"a": true, "b": true}},
+		{"other group", "table:2", map[string]bool{"b": true}},
+		{"unknown group", "table:3", nil},
+		{"everyone", "", map[string]bool{"a": true, "b": true, "c": true}},
+	}
+	conns := map[*Conn]*websocket.Conn{a: clientA, b: clientB, c: clientC}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := h.Broadcast(context.Background(), tt.group, []byte(tt.name)); err != nil {
+				t.Fatal(err)
+			}
+			for conn, ws := range conns {
+				if got := received(t, conn, ws, tt.name); got != tt.want[conn.ClientID] {
+					t.Errorf("%s received = %v, want %v", conn.ClientID, got, tt.want[conn.ClientID])
+				}
+			}
+		})
+	}
+}
+
+func TestLeaveAndClose(t *testing.T) {
+	h := New(DefaultConfig())
+	a, clientA := dial(t, h, "a")
+	b, _ := dial(t, h, "b")
+	h.Join(a, "table:1")
+	h.Join(b, "table:1")
+
+	h.Leave(a, "table:1")
+	h.Broadcast(context.Background(), "table:1", []byte("hello"))
+	if received(t, a, clientA, "hello") {
+		t.Error("connection that left the group received the broadcast")
+	}
+
+	b.Close(websocket.CloseNormalClosure, "")
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.groups) != 0 {
+		t.Errorf("closed connection left %d groups behind", len(h.groups))
+	}
+}
+
+func TestJoinAfterClose(t *testing.T) {
+	h := New(DefaultConfig())
+	a, _ := dial(t, h, "a")
+	a.Close(websocket.CloseNormalClosure, "")
+	h.Join(a, "table:1")
+
+	if members := h.members("table:1"); len(members) != 0 {
+		t.Errorf("closed connection joined the group")
+	}
+}
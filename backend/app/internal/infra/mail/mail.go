@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Transport entrega e-mails. Em desenvolvimento use FileTransport ou
+// LogTransport; em produção, SMTPTransport.
+type Transport interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPTransport struct {
+	config SMTPConfig
+}
+
+func NewSMTPTransport(config SMTPConfig) *SMTPTransport {
+	return &SMTPTransport{
+		config: config,
+	}
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(t.config.Host, t.config.Port)
+
+	var auth smtp.Auth
+	if t.config.Username != "" {
+		auth = smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)
+	}
+
+	// net/smtp não recebe contexto; o envio roda em uma goroutine para
+	// respeitar cancelamento e timeout de quem chamou
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, t.config.From, []string{msg.To}, render(t.config.From, msg))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			logger.Errorf("Failed to send email: %v", err)
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FileTransport grava cada mensagem renderizada no arquivo informado
+type FileTransport struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+func NewFileTransport(path, from string) *FileTransport {
+	return &FileTransport{
+		path: path,
+		from: from,
+	}
+}
+
+func (t *FileTransport) Send(ctx context.Context, msg Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := os.OpenFile(t.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		logger.Errorf("Failed to open mail file: %v", err)
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(render(t.from, msg), '\n'))
+	return err
+}
+
+type LogTransport struct{}
+
+func NewLogTransport() *LogTransport {
+	return &LogTransport{}
+}
+
+func (t *LogTransport) Send(ctx context.Context, msg Message) error {
+	logger.WithFields(logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info(msg.Body)
+	return nil
+}
+
+// remove quebras de linha dos cabeçalhos para evitar header injection
+var headerSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+func render(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerSanitizer.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerSanitizer.Replace(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerSanitizer.Replace(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,35 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/errs"
+	"game/api/internal/infra/session"
+)
+
+func (ws *WebServer) closeAccount(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.CloseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := ws.clientController.CloseAccount(r.Context(), clientID, req.Password)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidPassword) {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
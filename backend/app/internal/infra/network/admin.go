@@ -0,0 +1,236 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+)
+
+func (ws *WebServer) setupAdminRoutes() {
+	ws.Route("/admin", func(r chi.Router) {
+		r.Use(ws.sessionManager.Middleware)
+		r.Use(requireStaff)
+
+		r.With(requirePermission(entity.PermClientsRead)).Get("/clients", ws.adminSearchClients)
+		r.With(requirePermission(entity.PermClientsRead)).Get("/clients/{id}", ws.adminGetClient)
+		r.With(requirePermission(entity.PermWalletsRead)).Get("/clients/{id}/wallet", ws.adminGetWallet)
+		r.With(requirePermission(entity.PermBetsRead)).Get("/clients/{id}/bets", ws.adminListBets)
+		r.With(requirePermission(entity.PermClientsSuspend)).Post("/clients/{id}/suspend", ws.adminSuspend)
+		r.With(requirePermission(entity.PermClientsSuspend)).Post("/clients/{id}/unsuspend", ws.adminUnsuspend)
+		r.With(requirePermission(entity.PermClientsClose)).Post("/clients/{id}/close", ws.adminClose)
+		r.With(requirePermission(entity.PermClientsExport)).Get("/clients/{id}/export", ws.adminExport)
+		r.With(requirePermission(entity.PermClientsErase)).Post("/clients/{id}/erase", ws.adminErase)
+		r.With(requirePermission(entity.PermRolesManage)).Put("/clients/{id}/role", ws.adminSetRole)
+		r.With(requirePermission(entity.PermWalletsAdjust)).Post("/clients/{id}/adjustments", ws.adminAdjustBalance)
+		r.With(requirePermission(entity.PermWalletsAdjust)).Get("/adjustments", ws.adminListAdjustments)
+		r.With(requirePermission(entity.PermWalletsAdjust)).Post("/adjustments/{id}/approve", ws.adminApproveAdjustment)
+		r.With(requirePermission(entity.PermWalletsAdjust)).Post("/adjustments/{id}/reject", ws.adminRejectAdjustment)
+	})
+}
+
+func contextRole(r *http.Request) entity.Role {
+	role, _ := r.Context().Value(session.ContextKeyRole).(string)
+	parsed, _ := entity.ParseRole(role)
+	return parsed
+}
+
+func requireStaff(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !contextRole(r).IsStaff() {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func requirePermission(perm entity.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !contextRole(r).Can(perm) {
+				clientID, _ := r.Context().Value(session.ContextKeyClientID).(string)
+				logger.Warnf("Client %s denied %s on %s %s", clientID, perm, r.Method, r.URL.Path)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func pagination(r *http.Request) (limit, offset int) {
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+	return
+}
+
+func (ws *WebServer) adminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, errs.ErrForbidden):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, errs.ErrValidation):
+		ws.validationError(w, http.StatusUnprocessableEntity, err)
+	case errors.Is(err, errs.ErrInsufficientBalance):
+		http.Error(w, "Adjustment would make the balance negative", http.StatusConflict)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (ws *WebServer) adminSearchClients(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+	res, err := ws.adminController.SearchClients(r.Context(), r.URL.Query().Get("q"), limit, offset)
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminGetClient(w http.ResponseWriter, r *http.Request) {
+	res, err := ws.adminController.GetClient(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminGetWallet(w http.ResponseWriter, r *http.Request) {
+	res, err := ws.adminController.GetWallet(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminListBets(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+	res, err := ws.adminController.ListBets(r.Context(), chi.URLParam(r, "id"), limit, offset)
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminSuspend(w http.ResponseWriter, r *http.Request) {
+	ws.adminClientAction(w, r, ws.adminController.Suspend)
+}
+
+func (ws *WebServer) adminUnsuspend(w http.ResponseWriter, r *http.Request) {
+	ws.adminClientAction(w, r, ws.adminController.Unsuspend)
+}
+
+func (ws *WebServer) adminClose(w http.ResponseWriter, r *http.Request) {
+	ws.adminClientAction(w, r, ws.adminController.Close)
+}
+
+func (ws *WebServer) adminClientAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, adminID, clientID string) error,
+) {
+	adminID, _ := r.Context().Value(session.ContextKeyClientID).(string)
+	err := action(r.Context(), adminID, chi.URLParam(r, "id"))
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) adminSetRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value(session.ContextKeyClientID).(string)
+	err := ws.adminController.SetRole(r.Context(), adminID, chi.URLParam(r, "id"), req.Role)
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) adminAdjustBalance(w http.ResponseWriter, r *http.Request) {
+	var req dto.AdjustBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	adminID, _ := r.Context().Value(session.ContextKeyClientID).(string)
+	res, err := ws.adminController.AdjustBalance(r.Context(), adminID, chi.URLParam(r, "id"), req.Amount, req.Reason)
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	// pendente de aprovação: aceito, mas ainda não aplicado
+	if res.Status == string(entity.AdjustmentPending) {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminListAdjustments(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+	res, err := ws.adminController.ListAdjustments(r.Context(), r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminApproveAdjustment(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(session.ContextKeyClientID).(string)
+	res, err := ws.adminController.ApproveAdjustment(r.Context(), adminID, chi.URLParam(r, "id"))
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) adminRejectAdjustment(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(session.ContextKeyClientID).(string)
+	err := ws.adminController.RejectAdjustment(r.Context(), adminID, chi.URLParam(r, "id"))
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
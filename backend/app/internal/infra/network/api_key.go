@@ -0,0 +1,76 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/errs"
+	"game/api/internal/infra/session"
+)
+
+func (ws *WebServer) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := ws.apiKeyController.Create(r.Context(), clientID, req)
+	if err != nil {
+		if errors.Is(err, errs.ErrValidation) {
+			ws.validationError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	res, err := ws.apiKeyController.List(r.Context(), clientID)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) revokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	err := ws.apiKeyController.Revoke(r.Context(), clientID, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			http.Error(w, "API key not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
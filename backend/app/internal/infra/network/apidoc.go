@@ -0,0 +1,517 @@
+package network
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
+	"game/api/internal/domain/event"
+	"game/api/internal/infra/apidoc"
+	"game/api/internal/infra/logger"
+)
+
+const apiVersion = "1.0.0"
+
+var apiInfo = apidoc.Info{
+	Title:   "Game API",
+	Version: apiVersion,
+}
+
+// setupDocs publica /openapi.json e /asyncapi.json. Os schemas saem dos DTOs
+// e payloads usados pelos handlers; rotas e ações sem documentação são
+// apontadas na inicialização. Deve ser chamado depois de todas as rotas.
+func (ws *WebServer) setupDocs() {
+	ops := apiOperations()
+	ws.Get("/openapi.json", serveDoc(apidoc.OpenAPI(apiInfo, ops)))
+	ws.Get("/asyncapi.json", serveDoc(apidoc.AsyncAPI(apiInfo, []apidoc.Channel{wsChannel()})))
+
+	ws.checkDocs(ops)
+}
+
+func serveDoc(doc map[string]interface{}) http.HandlerFunc {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		logger.Errorf("Failed to generate API document: %v", err)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if data == nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(data)
+	}
+}
+
+func (ws *WebServer) checkDocs(ops []apidoc.Operation) {
+	routes, err := ws.routes()
+	if err != nil {
+		logger.Errorf("Failed to list routes: %v", err)
+		return
+	}
+
+	missing, stale := apidoc.Diff(routes, ops)
+	for _, route := range missing {
+		logger.Warnf("Route %s is not documented in /openapi.json", route)
+	}
+	for _, route := range stale {
+		logger.Warnf("Route %s is documented in /openapi.json but not registered", route)
+	}
+	for _, action := range undocumentedActions() {
+		logger.Warnf("WebSocket action %s is not documented in /asyncapi.json", action)
+	}
+}
+
+func (ws *WebServer) routes() ([]apidoc.Route, error) {
+	var routes []apidoc.Route
+	err := chi.Walk(ws.Mux, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, apidoc.Route{Method: method, Path: route})
+		return nil
+	})
+	return routes, err
+}
+
+// undocumentedActions retorna as ações do WebSocket sem requisição ou
+// resposta no canal documentado
+func undocumentedActions() []string {
+	ch := wsChannel()
+	requests := make(map[string]bool)
+	for _, m := range ch.Publish {
+		requests[m.Name] = true
+	}
+	replies := make(map[string]bool)
+	for _, m := range ch.Subscribe {
+		replies[m.Name] = true
+	}
+
+	var actions []string
+	for action := range actionScopes {
+		if !requests[action] || !replies[action] {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+func response(status int, description string, body interface{}) apidoc.Response {
+	return apidoc.Response{Status: status, Description: description, Body: body}
+}
+
+var (
+	validationFailed = response(http.StatusUnprocessableEntity, "Validation failed", dto.ValidationErrorResponse{})
+	rateLimited      = response(http.StatusTooManyRequests, "Too many requests, see Retry-After", nil)
+	adminNotFound    = response(http.StatusNotFound, "Not found", nil)
+	adminForbidden   = response(http.StatusForbidden, "Missing staff role or permission", nil)
+	mfaRequired      = response(http.StatusForbidden, "Two-factor confirmation (POST /2fa/confirm) older than 15 minutes", nil)
+)
+
+func apiOperations() []apidoc.Operation {
+	ops := []apidoc.Operation{
+		{
+			Method: http.MethodGet, Path: "/.well-known/jwks.json", Tag: "auth",
+			Summary:   "Public keys used to sign session tokens",
+			Responses: []apidoc.Response{response(http.StatusOK, "JWK set", nil)},
+		},
+		{
+			Method: http.MethodPost, Path: "/register", Tag: "auth",
+			Summary: "Create an account",
+			Request: dto.CreateClientRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "Account created", dto.CreateClientResponse{}),
+				response(http.StatusConflict, "Username or email already registered", dto.ValidationErrorResponse{}),
+				validationFailed,
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/login", Tag: "auth",
+			Summary: "Log in with username and password",
+			Request: dto.ClientLoginRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "Session token, or a partial token when mfa_required is set", dto.ClientLoginResponse{}),
+				response(http.StatusUnauthorized, "Invalid username or password", nil),
+				response(http.StatusForbidden, "Account suspended or closed", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/login/2fa", Tag: "auth", Auth: apidoc.AuthSession,
+			Summary: "Complete a two-factor login with the partial token",
+			Request: dto.MFACodeRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "Session token", dto.ClientLoginResponse{}),
+				response(http.StatusUnauthorized, "Invalid two-factor code", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/logout", Tag: "auth", Auth: apidoc.AuthSession,
+			Summary:   "Revoke the current session",
+			Responses: []apidoc.Response{response(http.StatusOK, "Session revoked", nil)},
+		},
+		{
+			Method: http.MethodPost, Path: "/session/reauth", Tag: "auth", Auth: apidoc.AuthSession,
+			Summary: "Bind the session to a new IP or user agent",
+			Request: dto.ReauthRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Session rebound", nil),
+				response(http.StatusUnauthorized, "Invalid password or two-factor code", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/email/verify", Tag: "email",
+			Summary: "Confirmation page for the emailed link; does not consume the token",
+			Query:   []apidoc.Param{{Name: "token", Description: "Verification token", Required: true}},
+			Responses: []apidoc.Response{
+				{Status: http.StatusOK, Description: "Page with a form that posts the token", ContentType: "text/html"},
+				{Status: http.StatusBadRequest, Description: "Missing token", ContentType: "text/html"},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/email/verify", Tag: "email",
+			Summary: "Verify an email address; the confirmation page posts the token as a form and gets HTML back",
+			Request: dto.VerifyEmailRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Email verified", nil),
+				response(http.StatusBadRequest, "Invalid or expired verification token", nil),
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/email/resend", Tag: "email", Auth: apidoc.AuthSession,
+			Summary: "Send the verification email again",
+			Responses: []apidoc.Response{
+				response(http.StatusAccepted, "Email queued", nil),
+				response(http.StatusBadRequest, "No email address registered", nil),
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/email", Tag: "email", Auth: apidoc.AuthSession,
+			Summary: "Change the email address, pending verification",
+			Request: dto.ChangeEmailRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusAccepted, "Verification email queued", nil),
+				response(http.StatusConflict, "Email already registered", dto.ValidationErrorResponse{}),
+				response(http.StatusForbidden, "Account closed", nil),
+				validationFailed,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/password/change", Tag: "password", Auth: apidoc.AuthSession,
+			Summary: "Change the password and revoke the other sessions",
+			Request: dto.ChangePasswordRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Password changed", nil),
+				response(http.StatusUnauthorized, "Invalid password", nil),
+				validationFailed,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/password/forgot", Tag: "password",
+			Summary: "Request a password reset email",
+			Request: dto.ForgotPasswordRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusAccepted, "Accepted, whether or not the user exists", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/password/reset", Tag: "password",
+			Summary: "Reset the password with an emailed token",
+			Request: dto.ResetPasswordRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Password reset", nil),
+				response(http.StatusBadRequest, "Invalid or expired reset token", nil),
+				response(http.StatusForbidden, "Account suspended", nil),
+				validationFailed,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/2fa/enroll", Tag: "2fa", Auth: apidoc.AuthSession,
+			Summary: "Start two-factor enrollment",
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "TOTP secret", dto.MFAEnrollResponse{}),
+				response(http.StatusConflict, "Two-factor authentication already enabled", nil),
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/2fa/enable", Tag: "2fa", Auth: apidoc.AuthSession,
+			Summary: "Enable two-factor authentication",
+			Request: dto.MFACodeRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "Recovery codes", dto.MFARecoveryCodesResponse{}),
+				response(http.StatusUnauthorized, "Invalid two-factor code", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/2fa/disable", Tag: "2fa", Auth: apidoc.AuthSession,
+			Summary: "Disable two-factor authentication",
+			Request: dto.MFACodeRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Disabled", nil),
+				response(http.StatusUnauthorized, "Invalid two-factor code", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/2fa/confirm", Tag: "2fa", Auth: apidoc.AuthSession,
+			Summary: "Confirm a sensitive operation with a two-factor code",
+			Request: dto.MFACodeRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Confirmed", nil),
+				response(http.StatusUnauthorized, "Invalid two-factor code", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/2fa/recovery-codes", Tag: "2fa", Auth: apidoc.AuthSession,
+			Summary: "Regenerate the recovery codes",
+			Request: dto.MFACodeRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "New recovery codes", dto.MFARecoveryCodesResponse{}),
+				response(http.StatusUnauthorized, "Invalid two-factor code", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/account/close", Tag: "account", Auth: apidoc.AuthSession,
+			Summary: "Close the account",
+			Request: dto.CloseAccountRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Account closed", nil),
+				response(http.StatusUnauthorized, "Invalid password", nil),
+				mfaRequired,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/account/export", Tag: "account", Auth: apidoc.AuthSession,
+			Summary: "Export the account data",
+			Query:   []apidoc.Param{{Name: "format", Description: "json for a single document; ZIP archive by default"}},
+			Responses: []apidoc.Response{
+				{Status: http.StatusOK, Description: "ZIP archive, or JSON with format=json", ContentType: "application/zip"},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/account/erase", Tag: "account", Auth: apidoc.AuthSession,
+			Summary: "Erase the personal data of the account",
+			Request: dto.EraseAccountRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Data erased", nil),
+				response(http.StatusUnauthorized, "Invalid password", nil),
+				mfaRequired,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/api-keys", Tag: "api-keys", Auth: apidoc.AuthSession,
+			Summary: "Create an API key; the full key is only returned here",
+			Request: dto.CreateAPIKeyRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusCreated, "API key created", dto.CreateAPIKeyResponse{}),
+				validationFailed,
+				mfaRequired,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/api-keys", Tag: "api-keys", Auth: apidoc.AuthSession,
+			Summary:   "List the active API keys",
+			Responses: []apidoc.Response{response(http.StatusOK, "API keys", dto.APIKeyListResponse{})},
+		},
+		{
+			Method: http.MethodDelete, Path: "/api-keys/{id}", Tag: "api-keys", Auth: apidoc.AuthSession,
+			Summary: "Revoke an API key",
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "API key revoked", nil),
+				response(http.StatusNotFound, "API key not found", nil),
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/wallet", Tag: "wallet",
+			Auth: apidoc.AuthSessionOrAPIKey, Scope: string(entity.ScopeWalletRead),
+			Summary:   "Get the wallet balance",
+			Responses: []apidoc.Response{response(http.StatusOK, "Balance", dto.GetBalanceResponse{}), rateLimited},
+		},
+		{
+			Method: http.MethodPost, Path: "/matches", Tag: "matches",
+			Auth: apidoc.AuthSessionOrAPIKey, Scope: string(entity.ScopeBetsPlace),
+			Summary: "Start a match",
+			Responses: []apidoc.Response{
+				response(http.StatusCreated, "Match started", dto.MatchResponse{}),
+				response(http.StatusForbidden, "Email not verified", nil),
+				response(http.StatusConflict, "Player already in match", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/matches/{id}/bets", Tag: "matches",
+			Auth: apidoc.AuthSessionOrAPIKey, Scope: string(entity.ScopeBetsPlace),
+			Summary: "Place a bet in the current match",
+			Request: dto.PlaceBetRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "Bet result", dto.PlaceBetResponse{}),
+				response(http.StatusNotFound, "Not the current match", nil),
+				response(http.StatusConflict, "Player not in match", nil),
+				response(http.StatusUnprocessableEntity, "Insufficient balance", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/matches/{id}/end", Tag: "matches",
+			Auth: apidoc.AuthSessionOrAPIKey, Scope: string(entity.ScopeBetsPlace),
+			Summary: "End the current match",
+			Responses: []apidoc.Response{
+				response(http.StatusNoContent, "Match ended", nil),
+				response(http.StatusNotFound, "Not the current match", nil),
+				response(http.StatusConflict, "Player not in match", nil),
+				rateLimited,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/ws", Tag: "realtime", Auth: apidoc.AuthSessionOrAPIKey,
+			Summary: "WebSocket connection, described in /asyncapi.json",
+			Query: []apidoc.Param{
+				{Name: "authorization", Description: "Bearer token, for clients that cannot set headers"},
+			},
+			Responses: []apidoc.Response{response(http.StatusSwitchingProtocols, "Connection upgraded", nil)},
+		},
+		{
+			Method: http.MethodGet, Path: "/events", Tag: "realtime", Auth: apidoc.AuthSessionOrAPIKey,
+			Summary: "Server-Sent Events with the WebSocket events; resumes from the Last-Event-ID header",
+			Query: []apidoc.Param{
+				{Name: "authorization", Description: "Bearer token, for EventSource clients"},
+			},
+			Responses: []apidoc.Response{
+				{Status: http.StatusOK, Description: "Event stream", ContentType: "text/event-stream"},
+				response(http.StatusServiceUnavailable, "Too many open streams, see Retry-After", nil),
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/rpc", Tag: "realtime", Auth: apidoc.AuthSessionOrAPIKey,
+			Summary: "JSON-RPC 2.0 call or batch (match.new, match.bet, match.end, wallet.get)",
+			Request: rpcRequest{},
+			Responses: []apidoc.Response{
+				response(http.StatusOK, "Response object, or an array for batches", rpcResponse{}),
+				response(http.StatusNoContent, "Only notifications were sent", nil),
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/openapi.json", Tag: "docs",
+			Summary:   "This document",
+			Responses: []apidoc.Response{response(http.StatusOK, "OpenAPI document", nil)},
+		},
+		{
+			Method: http.MethodGet, Path: "/asyncapi.json", Tag: "docs",
+			Summary:   "AsyncAPI document of the WebSocket protocol",
+			Responses: []apidoc.Response{response(http.StatusOK, "AsyncAPI document", nil)},
+		},
+	}
+	return append(ops, adminOperations()...)
+}
+
+func adminOperations() []apidoc.Operation {
+	admin := func(method, path, summary string, perm entity.Permission, request interface{}, responses ...apidoc.Response) apidoc.Operation {
+		return apidoc.Operation{
+			Method:    method,
+			Path:      path,
+			Tag:       "admin",
+			Auth:      apidoc.AuthSession,
+			Summary:   summary + " (" + string(perm) + ")",
+			Request:   request,
+			Responses: append(responses, adminForbidden),
+		}
+	}
+	noContent := response(http.StatusNoContent, "Done", nil)
+
+	search := admin(http.MethodGet, "/admin/clients", "Search clients", entity.PermClientsRead, nil,
+		response(http.StatusOK, "Clients", dto.AdminClientListResponse{}))
+	search.Query = []apidoc.Param{{Name: "q", Description: "Username or email"}, {Name: "limit"}, {Name: "offset"}}
+	bets := admin(http.MethodGet, "/admin/clients/{id}/bets", "Bet history", entity.PermBetsRead, nil,
+		response(http.StatusOK, "Bets", dto.AdminBetListResponse{}), adminNotFound)
+	bets.Query = []apidoc.Param{{Name: "limit"}, {Name: "offset"}}
+	adjustments := admin(http.MethodGet, "/admin/adjustments", "List balance adjustments", entity.PermWalletsAdjust, nil,
+		response(http.StatusOK, "Adjustments", dto.AdjustmentListResponse{}))
+	adjustments.Query = []apidoc.Param{{Name: "status", Description: "pending, applied or rejected"}}
+	export := admin(http.MethodGet, "/admin/clients/{id}/export", "Export client data", entity.PermClientsExport, nil,
+		apidoc.Response{Status: http.StatusOK, Description: "ZIP archive, or JSON with format=json", ContentType: "application/zip"}, adminNotFound)
+	export.Query = []apidoc.Param{{Name: "format", Description: "json for a single document; ZIP archive by default"}}
+
+	return []apidoc.Operation{
+		search,
+		admin(http.MethodGet, "/admin/clients/{id}", "Get a client", entity.PermClientsRead, nil,
+			response(http.StatusOK, "Client", dto.AdminClientResponse{}), adminNotFound),
+		admin(http.MethodGet, "/admin/clients/{id}/wallet", "Get a client wallet", entity.PermWalletsRead, nil,
+			response(http.StatusOK, "Wallet", dto.AdminWalletResponse{}), adminNotFound),
+		bets,
+		admin(http.MethodPost, "/admin/clients/{id}/suspend", "Suspend a client and revoke their sessions", entity.PermClientsSuspend, nil,
+			noContent, adminNotFound),
+		admin(http.MethodPost, "/admin/clients/{id}/unsuspend", "Lift a suspension", entity.PermClientsSuspend, nil,
+			noContent, adminNotFound),
+		admin(http.MethodPost, "/admin/clients/{id}/close", "Close a client account", entity.PermClientsClose, nil,
+			noContent, adminNotFound),
+		export,
+		admin(http.MethodPost, "/admin/clients/{id}/erase", "Erase client personal data", entity.PermClientsErase, nil,
+			noContent, adminNotFound),
+		admin(http.MethodPut, "/admin/clients/{id}/role", "Change a client role and revoke their sessions", entity.PermRolesManage, dto.SetRoleRequest{},
+			noContent, adminNotFound, validationFailed),
+		admin(http.MethodPost, "/admin/clients/{id}/adjustments", "Adjust a client balance", entity.PermWalletsAdjust, dto.AdjustBalanceRequest{},
+			response(http.StatusCreated, "Adjustment applied", dto.AdjustmentResponse{}),
+			response(http.StatusAccepted, "Adjustment pending approval", dto.AdjustmentResponse{}),
+			response(http.StatusConflict, "Adjustment would make the balance negative", nil),
+			adminNotFound, validationFailed),
+		adjustments,
+		admin(http.MethodPost, "/admin/adjustments/{id}/approve", "Approve and apply a pending adjustment", entity.PermWalletsAdjust, nil,
+			response(http.StatusOK, "Adjustment applied", dto.AdjustmentResponse{}), adminNotFound),
+		admin(http.MethodPost, "/admin/adjustments/{id}/reject", "Reject a pending adjustment", entity.PermWalletsAdjust, nil,
+			noContent, adminNotFound),
+	}
+}
+
+// wsChannel descreve o protocolo v2 em JSON; a v1 e o msgpack usam as mesmas
+// mensagens
+func wsChannel() apidoc.Channel {
+	request := func(action, summary string, payload interface{}) apidoc.Message {
+		return apidoc.Message{Key: "request." + action, Name: action, Summary: summary, Payload: payload}
+	}
+	reply := func(action, summary string, payload interface{}) apidoc.Message {
+		return apidoc.Message{Key: "response." + action, Name: action, Summary: summary, Payload: payload}
+	}
+	push := func(t event.Type, summary string, payload interface{}) apidoc.Message {
+		return apidoc.Message{Key: "event." + string(t), Name: string(t), Summary: summary, Payload: payload}
+	}
+
+	return apidoc.Channel{
+		Path: "/ws",
+		Description: "Negotiate the protocol version with the game.v2, game.v2.msgpack or game.v1 subprotocol, or with a hello " +
+			"first message. Errors keep the request action and carry an error object.",
+		PublishEnvelope: apidoc.Envelope{Type: wireRequest{}, NameField: "action", BodyField: "data"},
+		Publish: []apidoc.Message{
+			request(ActionHello, "Negotiate the protocol version and encoding", HelloPayload{}),
+			request(ActionResume, "Replay the messages missed by a dropped connection; must precede other requests (v2)", ResumePayload{}),
+			request(ActionNewMatch, "Start a match", nil),
+			request(ActionPlaceBet, "Place a bet in the current match", PlaceBetPayload{}),
+			request(ActionWallet, "Get the wallet balance", nil),
+			request(ActionEndMatch, "End the current match", nil),
+		},
+		SubscribeEnvelope: apidoc.Envelope{Type: WSResponse{}, NameField: "action", BodyField: "data"},
+		Subscribe: []apidoc.Message{
+			reply(ActionHello, "Negotiated protocol", helloResponse{}),
+			reply(ActionSession, "Sent when a v2 connection opens, with the token to resume it", sessionResponse{}),
+			reply(ActionResume, "Sent after the replayed messages", resumeResponse{}),
+			reply(ActionNewMatch, "Match started", dto.MatchResponse{}),
+			reply(ActionPlaceBet, "Bet result", dto.PlaceBetResponse{}),
+			reply(ActionWallet, "Balance", dto.GetBalanceResponse{}),
+			reply(ActionEndMatch, "Match ended", nil),
+			push(event.BalanceUpdated, "Balance changed after a bet or a manual adjustment", dto.BalanceUpdatedEvent{}),
+			push(event.BetSettled, "A bet was settled", dto.BetSettledEvent{}),
+			push(event.MatchEnded, "The match ended", dto.MatchEndedEvent{}),
+			push(event.SessionRevoked, "The session was revoked; the connection is closed with code 4001", dto.SessionRevokedEvent{}),
+			push(event.APIKeyRevoked, "The API key was revoked; its connections are closed with code 4003", dto.APIKeyRevokedEvent{}),
+		},
+	}
+}
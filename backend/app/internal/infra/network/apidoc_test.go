@@ -0,0 +1,176 @@
+package network
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"game/api/internal/infra/apidoc"
+	"game/api/internal/infra/hub"
+	"game/api/internal/infra/session"
+)
+
+// nomes de campos JSON dos DTOs; um campo sem tag json aparece com o nome Go
+var jsonField = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// newDocsServer monta as rotas sem dependências: os handlers não são
+// chamados
+func newDocsServer(t *testing.T) *WebServer {
+	t.Helper()
+	connHub := hub.New(hub.DefaultConfig())
+	return NewWebServer(
+		nil, nil, nil, nil, nil, nil, nil,
+		session.NewManager(nil, 0, nil, session.BindingConfig{}),
+		connHub,
+		connHub,
+		nil,
+		nil,
+		DefaultRateLimits(),
+		DefaultCORSConfig(),
+	)
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	ws := newDocsServer(t)
+	routes, err := ws.routes()
+	if err != nil {
+		t.Fatalf("walking routes: %v", err)
+	}
+	if len(routes) == 0 {
+		t.Fatal("no routes registered")
+	}
+
+	missing, stale := apidoc.Diff(routes, apiOperations())
+	for _, route := range missing {
+		t.Errorf("route %s is not documented in apiOperations", route)
+	}
+	for _, route := range stale {
+		t.Errorf("route %s is documented but not registered", route)
+	}
+}
+
+func TestActionsDocumented(t *testing.T) {
+	for _, action := range undocumentedActions() {
+		t.Errorf("WebSocket action %s has no documented request or response", action)
+	}
+}
+
+func TestOperationsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, op := range apiOperations() {
+		if seen[op.Key()] {
+			t.Errorf("operation %s is documented twice", op.Key())
+		}
+		seen[op.Key()] = true
+		if len(op.Responses) == 0 {
+			t.Errorf("operation %s has no responses", op.Key())
+		}
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	bodies := make(map[string]interface{})
+	for _, op := range apiOperations() {
+		if op.Request != nil {
+			bodies[op.Key()+" request"] = op.Request
+		}
+		for _, r := range op.Responses {
+			if r.Body != nil {
+				bodies[op.Key()+" response "+strconv.Itoa(r.Status)] = r.Body
+			}
+		}
+	}
+	ch := wsChannel()
+	for _, m := range append(append([]apidoc.Message{}, ch.Publish...), ch.Subscribe...) {
+		if m.Payload != nil {
+			bodies["message "+m.Key] = m.Payload
+		}
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			schemas := apidoc.NewSchemas("#/components/schemas/")
+			schemas.Of(body)
+			for component, schema := range schemas.Components() {
+				if schema.Type == "object" && len(schema.Properties) == 0 && !isEmptyStruct(body, component) {
+					t.Errorf("schema %s has no JSON fields", component)
+				}
+				for field := range schema.Properties {
+					if !jsonField.MatchString(field) {
+						t.Errorf("schema %s field %q is missing a snake_case json tag", component, field)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentReferences(t *testing.T) {
+	docs := map[string]map[string]interface{}{
+		"openapi":  apidoc.OpenAPI(apiInfo, apiOperations()),
+		"asyncapi": apidoc.AsyncAPI(apiInfo, []apidoc.Channel{wsChannel()}),
+	}
+	for name, doc := range docs {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var tree interface{}
+			if err := json.Unmarshal(data, &tree); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			var refs []string
+			collectRefs(tree, &refs)
+			for _, ref := range refs {
+				if !resolves(tree, ref) {
+					t.Errorf("reference %s does not resolve", ref)
+				}
+			}
+		})
+	}
+}
+
+// isEmptyStruct aceita structs sem campos de propósito (corpo "{}")
+func isEmptyStruct(body interface{}, component string) bool {
+	t := reflect.TypeOf(body)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name() == component && t.Kind() == reflect.Struct && t.NumField() == 0
+}
+
+func collectRefs(node interface{}, refs *[]string) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				*refs = append(*refs, ref)
+				continue
+			}
+			collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range n {
+			collectRefs(value, refs)
+		}
+	}
+}
+
+func resolves(tree interface{}, ref string) bool {
+	node := tree
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if node, ok = m[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package network
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+)
+
+const anyOrigin = "*"
+
+var (
+	corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+	corsHeaders = []string{"Authorization", "Content-Type", "X-API-Key", "Last-Event-ID"}
+	// lidos pelo frontend nas respostas
+	corsExposed = []string{"Retry-After", "Location"}
+)
+
+type CORSConfig struct {
+	// origens de navegador aceitas no REST e no WebSocket, além da própria
+	// origem da API; "*" aceita qualquer uma
+	AllowedOrigins []string
+	// por quanto tempo o navegador guarda a resposta do preflight
+	MaxAge time.Duration
+}
+
+// DefaultCORSConfig aceita o frontend servido pelo nginx do docker-compose
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"http://localhost"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// ParseOrigins aceita uma lista separada por vírgulas de origens no formato
+// esquema://host[:porta], ou "*"
+func ParseOrigins(s string) ([]string, error) {
+	var origins []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == anyOrigin {
+			origins = append(origins, part)
+			continue
+		}
+		u, err := url.Parse(part)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return nil, fmt.Errorf("invalid origin %q, expected scheme://host[:port]", part)
+		}
+		origins = append(origins, strings.ToLower(u.Scheme+"://"+u.Host))
+	}
+	return origins, nil
+}
+
+// allowedOrigin aceita requisições sem Origin (clientes que não são
+// navegadores), da mesma origem da API ou de uma origem da lista
+func (c CORSConfig) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return c.listed(origin)
+}
+
+func (c CORSConfig) listed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == anyOrigin || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOrigin recusa o upgrade do WebSocket vindo de páginas de outras
+// origens, que enviariam o token de query string ou a chave de API da vítima
+// (cross-site WebSocket hijacking)
+func (ws *WebServer) checkOrigin(r *http.Request) bool {
+	if ws.cors.allowedOrigin(r) {
+		return true
+	}
+	logger.WithFields(logrus.Fields{
+		"origin": r.Header.Get("Origin"),
+	}).Warn("WebSocket origin rejected")
+	return false
+}
+
+// corsMiddleware libera as rotas REST para as origens da lista. A
+// autenticação é por header, então não há credenciais (cookies) a liberar.
+func (ws *WebServer) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		w.Header().Add("Vary", "Origin")
+		if origin != "" && ws.cors.listed(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if preflight {
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join(corsMethods, ", "))
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(corsHeaders, ", "))
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(ws.cors.MaxAge.Seconds())))
+			} else {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(corsExposed, ", "))
+			}
+		}
+
+		// sem os headers acima o navegador bloqueia a requisição seguinte
+		if preflight {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
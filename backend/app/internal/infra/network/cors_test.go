@@ -0,0 +1,134 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"single", "http://localhost", []string{"http://localhost"}, false},
+		{"list with spaces", " https://a.example.com , http://localhost:3000 ", []string{"https://a.example.com", "http://localhost:3000"}, false},
+		{"empty entries skipped", "http://localhost,,", []string{"http://localhost"}, false},
+		{"lowercased", "HTTPS://App.Example.COM", []string{"https://app.example.com"}, false},
+		{"trailing slash", "https://app.example.com/", []string{"https://app.example.com"}, false},
+		{"any", "*", []string{"*"}, false},
+		{"no scheme", "app.example.com", nil, true},
+		{"no host", "https://", nil, true},
+		{"with path", "https://app.example.com/admin", nil, true},
+		{"with query", "https://app.example.com?x=1", nil, true},
+		{"one invalid fails all", "http://localhost,example.com", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOrigins(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseOrigins(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOrigins(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOrigins(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	listed := CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}
+	wildcard := CORSConfig{AllowedOrigins: []string{anyOrigin}}
+	none := CORSConfig{}
+
+	tests := []struct {
+		name   string
+		config CORSConfig
+		host   string
+		origin string
+		want   bool
+	}{
+		{"no origin", none, "api.example.com", "", true},
+		{"same origin", none, "api.example.com", "https://api.example.com", true},
+		{"same origin ignores case", none, "api.example.com", "https://API.example.com", true},
+		{"same host other port", none, "api.example.com", "https://api.example.com:8443", false},
+		{"listed", listed, "api.example.com", "https://app.example.com", true},
+		{"listed ignores case", listed, "api.example.com", "https://APP.example.com", true},
+		{"listed with other scheme", listed, "api.example.com", "http://app.example.com", false},
+		{"not listed", listed, "api.example.com", "https://evil.example.com", false},
+		{"suffix of a listed origin", listed, "api.example.com", "https://app.example.com.evil.com", false},
+		{"null origin", listed, "api.example.com", "null", false},
+		{"any", wildcard, "api.example.com", "https://evil.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			r.Host = tt.host
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := tt.config.allowedOrigin(r); got != tt.want {
+				t.Errorf("allowedOrigin(%q on %s) = %v, want %v", tt.origin, tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	ws := &WebServer{cors: CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		preflight  bool
+		wantStatus int
+		wantAllow  string
+	}{
+		{"listed request", http.MethodGet, "https://app.example.com", false, http.StatusOK, "https://app.example.com"},
+		{"listed preflight", http.MethodOptions, "https://app.example.com", true, http.StatusNoContent, "https://app.example.com"},
+		{"unlisted request", http.MethodGet, "https://evil.example.com", false, http.StatusOK, ""},
+		// o preflight termina aqui mesmo sem liberar: o navegador bloqueia
+		{"unlisted preflight", http.MethodOptions, "https://evil.example.com", true, http.StatusNoContent, ""},
+		{"no origin", http.MethodGet, "", false, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/clients", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			ws.corsMiddleware(next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllow)
+			}
+			if got := w.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("Vary = %q, want Origin", got)
+			}
+			if tt.preflight && tt.wantAllow != "" && w.Header().Get("Access-Control-Allow-Methods") == "" {
+				t.Error("preflight without Access-Control-Allow-Methods")
+			}
+		})
+	}
+}
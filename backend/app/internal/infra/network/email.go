@@ -0,0 +1,145 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"mime"
+	"net/http"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+)
+
+// verifyEmailPage é a página aberta pelo link do e-mail. O GET não consome o
+// token, porque clientes de e-mail e antivírus abrem os links antes do
+// usuário; apenas o POST do formulário confirma.
+var verifyEmailPage = template.Must(template.New("verify_email").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Email verification</title></head>
+<body>
+{{if .Token}}<form method="post" action="verify">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Confirm email</button>
+</form>{{else}}<p>{{.Message}}</p>{{end}}
+</body>
+</html>
+`))
+
+type verifyEmailView struct {
+	Token   string
+	Message string
+}
+
+func (ws *WebServer) verifyEmailForm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeVerifyEmailPage(w, http.StatusBadRequest, verifyEmailView{Message: "Invalid or expired verification link."})
+		return
+	}
+	writeVerifyEmailPage(w, http.StatusOK, verifyEmailView{Token: token})
+}
+
+// verifyEmail recebe o JSON do frontend ou o formulário da página do link,
+// respondendo no mesmo formato
+func (ws *WebServer) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	form := isFormRequest(r)
+
+	var token string
+	if form {
+		token = r.PostFormValue("token")
+	} else {
+		var req dto.VerifyEmailRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		token = req.Token
+	}
+
+	err := ws.clientController.VerifyEmail(r.Context(), token)
+	if err != nil {
+		status, message := http.StatusInternalServerError, "Internal server error"
+		if errors.Is(err, errs.ErrInvalidVerificationToken) {
+			status, message = http.StatusBadRequest, "Invalid or expired verification token"
+		}
+		if form {
+			writeVerifyEmailPage(w, status, verifyEmailView{Message: message + "."})
+			return
+		}
+		http.Error(w, message, status)
+		return
+	}
+	if form {
+		writeVerifyEmailPage(w, http.StatusOK, verifyEmailView{Message: "Email verified."})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func isFormRequest(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/x-www-form-urlencoded"
+}
+
+func writeVerifyEmailPage(w http.ResponseWriter, status int, view verifyEmailView) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// o token está na URL da página
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(status)
+	if err := verifyEmailPage.Execute(w, view); err != nil {
+		logger.Errorf("Failed to render email verification page: %v", err)
+	}
+}
+
+func (ws *WebServer) resendVerification(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	err := ws.clientController.ResendVerification(r.Context(), clientID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			http.Error(w, "No email address registered", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (ws *WebServer) changeEmail(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := ws.clientController.ChangeEmail(r.Context(), clientID, req.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrEmailExists):
+			ws.validationError(w, http.StatusConflict, err)
+		case errors.Is(err, errs.ErrNotFound):
+			// a conta foi encerrada depois da autenticação
+			http.Error(w, "Account closed", http.StatusForbidden)
+		case errors.Is(err, errs.ErrValidation):
+			ws.validationError(w, http.StatusUnprocessableEntity, err)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
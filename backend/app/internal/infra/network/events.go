@@ -0,0 +1,99 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/application/controller"
+	"game/api/internal/domain/event"
+	"game/api/internal/infra/eventlog"
+	"game/api/internal/infra/logger"
+)
+
+// códigos de fechamento da faixa de aplicação (4000-4999)
+const (
+	closeSessionRevoked = 4001
+	closeAPIKeyRevoked  = 4003
+)
+
+// tempo máximo para gravar e publicar um evento no Redis
+const pushTimeout = 2 * time.Second
+
+// PushEvent entrega eventos de domínio a todas as conexões abertas do
+// cliente, em qualquer instância. Deve ser assinado no event.Bus.
+func (ws *WebServer) PushEvent(ctx context.Context, e event.Event) {
+	// o evento já aconteceu: não se perde se a requisição que o publicou for
+	// cancelada, e o publicador não espera mais que pushTimeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pushTimeout)
+	defer cancel()
+
+	payload, ok := controller.EventPayload(e)
+	if !ok {
+		return
+	}
+	response := ws.successResponse(string(e.Type), payload)
+	msg, err := json.Marshal(response)
+	if err != nil {
+		logger.Errorf("Failed to marshal event: %v", err)
+		return
+	}
+	clientID := e.ClientID.String()
+	if response.Seq = ws.recordEvent(ctx, clientID, e, msg); response.Seq != "" {
+		if msg, err = json.Marshal(response); err != nil {
+			logger.Errorf("Failed to marshal event: %v", err)
+			return
+		}
+	}
+
+	// só as conexões das sessões revogadas são avisadas e encerradas; as
+	// demais sessões do cliente continuam conectadas
+	switch p := e.Payload.(type) {
+	case event.SessionRevokedPayload:
+		err = ws.router.CloseSessions(ctx, clientID, p.Tokens, msg, closeSessionRevoked, "session revoked")
+	case event.APIKeyRevokedPayload:
+		err = ws.router.CloseAPIKeys(ctx, clientID, []string{p.KeyID}, msg, closeAPIKeyRevoked, "api key revoked")
+	default:
+		err = ws.router.SendToClient(ctx, clientID, msg)
+	}
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"client_id": clientID,
+			"event":     e.Type,
+		}).Errorf("Failed to push event: %v", err)
+	}
+}
+
+// recordEvent guarda o evento no buffer usado pelo SSE e pela retomada do
+// WebSocket e retorna seu ID. As sessões revogadas são identificadas pelo
+// hash do token, e as chaves revogadas por apiKeyTag.
+func (ws *WebServer) recordEvent(ctx context.Context, clientID string, e event.Event, msg []byte) string {
+	if ws.eventLog == nil {
+		return ""
+	}
+
+	entry := eventlog.Entry{Message: msg}
+	switch p := e.Payload.(type) {
+	case event.SessionRevokedPayload:
+		entry.Close = true
+		if p.Tokens != nil {
+			entry.Sessions = make([]string, 0, len(p.Tokens))
+			for _, token := range p.Tokens {
+				entry.Sessions = append(entry.Sessions, sessionTag(token))
+			}
+		}
+	case event.APIKeyRevokedPayload:
+		entry.Close = true
+		entry.Sessions = []string{apiKeyTag(p.KeyID)}
+	}
+	id, err := ws.eventLog.Append(ctx, clientID, entry)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"client_id": clientID,
+			"event":     e.Type,
+		}).Errorf("Failed to record event: %v", err)
+	}
+	return id
+}
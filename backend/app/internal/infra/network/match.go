@@ -0,0 +1,110 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
+	"game/api/internal/infra/session"
+)
+
+// Rotas REST equivalentes às ações new_match, place_bet e end_match do
+// WebSocket, para clientes que não mantêm uma conexão aberta.
+func (ws *WebServer) setupMatchRoutes() {
+	scope := string(entity.ScopeBetsPlace)
+	ws.Post("/matches", ws.sessionManager.ValidateJWTOrAPIKey(scope, ws.limitAction(ActionNewMatch, ws.newMatch)))
+	ws.Post("/matches/{id}/bets", ws.sessionManager.ValidateJWTOrAPIKey(scope, ws.limitAction(ActionPlaceBet, ws.placeBet)))
+	ws.Post("/matches/{id}/end", ws.sessionManager.ValidateJWTOrAPIKey(scope, ws.limitAction(ActionEndMatch, ws.endMatch)))
+}
+
+func (ws *WebServer) newMatch(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	res, err := ws.matchController.NewMatch(r.Context(), clientID)
+	if err != nil {
+		ws.matchError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/matches/"+res.MatchID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) placeBet(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.PlaceBetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := ws.matchController.RequireMatch(r.Context(), clientID, chi.URLParam(r, "id")); err != nil {
+		ws.matchError(w, err)
+		return
+	}
+	res, err := ws.matchController.Bet(r.Context(), clientID, req.Amount, req.Choice)
+	if err != nil {
+		ws.matchError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) endMatch(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := ws.matchController.RequireMatch(r.Context(), clientID, chi.URLParam(r, "id")); err != nil {
+		ws.matchError(w, err)
+		return
+	}
+	if err := ws.matchController.EndMatch(r.Context(), clientID); err != nil {
+		ws.matchError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) matchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		http.Error(w, "Match not found", http.StatusNotFound)
+	case errors.Is(err, errs.ErrEmailNotVerified):
+		http.Error(w, "Email not verified", http.StatusForbidden)
+	case errors.Is(err, errs.ErrAccountSuspended):
+		http.Error(w, "Account suspended", http.StatusForbidden)
+	case errors.Is(err, errs.ErrPlayerAlreadyInMatch):
+		http.Error(w, "Player already in match", http.StatusConflict)
+	case errors.Is(err, errs.ErrPlayerNotInMatch):
+		http.Error(w, "Player not in match", http.StatusConflict)
+	case errors.Is(err, errs.ErrInsufficientBalance):
+		http.Error(w, "Insufficient balance", http.StatusUnprocessableEntity)
+	case errors.Is(err, errs.ErrValidation):
+		ws.validationError(w, http.StatusUnprocessableEntity, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		http.Error(w, "Operation timed out, please try again", http.StatusGatewayTimeout)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,82 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/errs"
+	"game/api/internal/infra/session"
+)
+
+func (ws *WebServer) changePassword(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, _ := r.Context().Value(session.ContextKeyToken).(string)
+	err := ws.passwordController.Change(r.Context(), clientID, token, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidPassword) {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, errs.ErrValidation) {
+			ws.validationError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) forgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := ws.passwordController.Forgot(r.Context(), req.Username); err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	// sempre 202, exista ou não o username
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (ws *WebServer) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := ws.passwordController.Reset(r.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidResetToken) {
+			http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, errs.ErrAccountSuspended) {
+			http.Error(w, "Account suspended", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, errs.ErrValidation) {
+			ws.validationError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package network
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+)
+
+func (ws *WebServer) exportData(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	res, err := ws.privacyController.Export(r.Context(), clientID)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	ws.writeExport(w, r, res)
+}
+
+func (ws *WebServer) eraseAccount(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.EraseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := ws.privacyController.Erase(r.Context(), clientID, req.Password)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidPassword) {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) adminExport(w http.ResponseWriter, r *http.Request) {
+	res, err := ws.adminController.Export(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		ws.adminError(w, err)
+		return
+	}
+	ws.writeExport(w, r, res)
+}
+
+func (ws *WebServer) adminErase(w http.ResponseWriter, r *http.Request) {
+	ws.adminClientAction(w, r, ws.adminController.Erase)
+}
+
+// writeExport responde com um ZIP (um arquivo JSON por seção) ou, com
+// ?format=json, com um único documento JSON.
+func (ws *WebServer) writeExport(w http.ResponseWriter, r *http.Request, res dto.DataExportResponse) {
+	name := fmt.Sprintf("export-%s-%s", res.Profile.ID, res.GeneratedAt.Format("20060102T150405Z"))
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, name))
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", res.Profile},
+		{"wallet.json", res.Wallet},
+		{"sessions.json", res.Sessions},
+		{"bets.json", res.Bets},
+		{"transactions.json", res.Transactions},
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, name))
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: res.GeneratedAt,
+		})
+		if err != nil {
+			logger.Errorf("Failed to write export archive: %v", err)
+			return
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			logger.Errorf("Failed to write export archive: %v", err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logger.Errorf("Failed to write export archive: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/ratelimit"
+	"game/api/internal/infra/session"
+)
+
+const (
+	rateLimitLogin      = "login"
+	rateLimitRegister   = "register"
+	rateLimitPassword   = "password_reset"
+	rateLimitConnection = "connection"
+	rateLimitClient     = "client"
+)
+
+type contextKey string
+
+// baldes da conexão WebSocket, que vivem nesta instância
+const contextKeyConnLimiter contextKey = "conn_limiter"
+
+// RateLimits são os limites de requisições. Ações sem limite configurado não
+// são limitadas.
+type RateLimits struct {
+	// por IP
+	Login         ratelimit.Limit
+	Register      ratelimit.Limit
+	PasswordReset ratelimit.Limit
+	// por conexão WebSocket e ação
+	Connection map[string]ratelimit.Limit
+	// por cliente e ação, somando conexões, instâncias, JSON-RPC e REST
+	Client map[string]ratelimit.Limit
+}
+
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Login:         ratelimit.Limit{Burst: 10, Period: time.Minute},
+		Register:      ratelimit.Limit{Burst: 10, Period: time.Hour},
+		PasswordReset: ratelimit.Limit{Burst: 5, Period: time.Hour},
+		Connection: map[string]ratelimit.Limit{
+			ActionNewMatch: {Burst: 5, Period: time.Second},
+			ActionPlaceBet: {Burst: 10, Period: time.Second},
+			ActionWallet:   {Burst: 10, Period: time.Second},
+			ActionEndMatch: {Burst: 5, Period: time.Second},
+		},
+		Client: map[string]ratelimit.Limit{
+			ActionNewMatch: {Burst: 10, Period: time.Second},
+			ActionPlaceBet: {Burst: 20, Period: time.Second},
+			ActionWallet:   {Burst: 20, Period: time.Second},
+			ActionEndMatch: {Burst: 10, Period: time.Second},
+		},
+	}
+}
+
+// limitByIP é um middleware do chi para rotas sem autenticação
+func (ws *WebServer) limitByIP(name string, limit ratelimit.Limit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := ws.allow(r.Context(), name, ws.sessionManager.ClientIP(r), limit)
+			if err != nil {
+				session.WriteRateLimitError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitAction aplica às rotas REST o limite por cliente da ação equivalente
+// do WebSocket. Deve ser usado dentro da autenticação.
+func (ws *WebServer) limitAction(action string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := ws.allowAction(r.Context(), action); err != nil {
+			session.WriteRateLimitError(w, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// withConnLimiter dá à conexão WebSocket seus próprios baldes
+func withConnLimiter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyConnLimiter, ratelimit.NewMemory())
+}
+
+// allowAction aplica os limites da conexão, se houver, e do cliente
+func (ws *WebServer) allowAction(ctx context.Context, action string) error {
+	if conn, ok := ctx.Value(contextKeyConnLimiter).(*ratelimit.Memory); ok {
+		err := ratelimit.Allow(ctx, conn, rateLimitConnection, action, ws.limits.Connection[action])
+		if err != nil {
+			return err
+		}
+	}
+	clientID, _ := ctx.Value(session.ContextKeyClientID).(string)
+	return ws.allow(ctx, rateLimitClient+":"+action, clientID, ws.limits.Client[action])
+}
+
+// allow só recusa quando o limite foi atingido; se o armazenamento falhar a
+// requisição segue, para que o Redis fora do ar não derrube a API
+func (ws *WebServer) allow(ctx context.Context, name, key string, limit ratelimit.Limit) error {
+	if ws.limiter == nil {
+		return nil
+	}
+	err := ratelimit.Allow(ctx, ws.limiter, name, key, limit)
+	var retryErr *errs.RetryError
+	if err != nil && !errors.As(err, &retryErr) {
+		logger.WithFields(logrus.Fields{
+			"limit": name,
+		}).Errorf("Failed to apply rate limit: %v", err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	"game/api/internal/infra/hub"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+)
+
+const (
+	jsonRPCVersion = "2.0"
+
+	// SubprotocolJSONRPC troca o protocolo de /ws por JSON-RPC 2.0
+	SubprotocolJSONRPC = "jsonrpc"
+
+	// eventos do servidor chegam como notificações event.<tipo>
+	rpcEventPrefix = "event."
+
+	maxRPCBody  = 1 << 20
+	maxRPCBatch = 50
+)
+
+// Códigos de erro do JSON-RPC 2.0. Erros de negócio usam rpcServerError e
+// trazem o código do protocolo WebSocket em data.code.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	rpcServerError    = -32000
+)
+
+var (
+	errMethodNotFound = errors.New("method not found")
+	errInvalidParams  = errors.New("params must be an object")
+)
+
+// métodos JSON-RPC e as ações equivalentes do protocolo WebSocket
+var rpcMethods = map[string]string{
+	"match.new":  ActionNewMatch,
+	"match.bet":  ActionPlaceBet,
+	"match.end":  ActionEndMatch,
+	"wallet.get": ActionWallet,
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	// ausente em notificações; null é um id válido
+	ID json.RawMessage `json:"id,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+type rpcNotification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+var rpcNull = json.RawMessage("null")
+
+// rpc atende JSON-RPC sobre HTTP. Um corpo só com notificações não tem
+// resposta (204).
+func (ws *WebServer) rpc(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRPCBody))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, ok := ws.handleRPC(r.Context(), body)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// handleRPCConnection é o goroutine de leitura de conexões abertas com o
+// subprotocolo jsonrpc. Cada mensagem é uma requisição ou um lote.
+func (ws *WebServer) handleRPCConnection(ctx context.Context, client *hub.Conn) {
+	logger.Infof("New JSON-RPC WebSocket connection established for client %s", client.ClientID)
+	defer client.Close(websocket.CloseNormalClosure, "")
+
+	client.SetEncoding(rpcPush)
+	for {
+		msg, err := client.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure, websocket.CloseNormalClosure) {
+				logger.Errorf("Error reading message: %v", err)
+			}
+			return
+		}
+
+		msgCtx, cancel := messageContext(ctx)
+		res, ok := ws.handleRPC(msgCtx, msg)
+		cancel()
+		if !ok {
+			continue
+		}
+
+		data, err := json.Marshal(res)
+		if err != nil {
+			logger.Errorf("Failed to encode JSON-RPC response: %v", err)
+			return
+		}
+		if !client.SendEncoded(data, false) {
+			return
+		}
+	}
+}
+
+// handleRPC retorna a resposta (um objeto ou, para lotes, uma lista) e se há
+// algo a responder. As chamadas de um lote são executadas em ordem.
+func (ws *WebServer) handleRPC(ctx context.Context, data []byte) (interface{}, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		res := ws.callRPC(ctx, data)
+		return res, res != nil
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return rpcFailure(rpcNull, &rpcError{Code: rpcParseError, Message: "parse error"}), true
+	}
+	if len(batch) == 0 {
+		return rpcFailure(rpcNull, &rpcError{Code: rpcInvalidRequest, Message: "empty batch"}), true
+	}
+	if len(batch) > maxRPCBatch {
+		return rpcFailure(rpcNull, &rpcError{Code: rpcInvalidRequest, Message: "batch too large"}), true
+	}
+
+	responses := make([]*rpcResponse, 0, len(batch))
+	for _, raw := range batch {
+		if res := ws.callRPC(ctx, raw); res != nil {
+			responses = append(responses, res)
+		}
+	}
+	return responses, len(responses) > 0
+}
+
+// callRPC executa uma chamada; retorna nil para notificações, inclusive
+// quando falham.
+func (ws *WebServer) callRPC(ctx context.Context, raw json.RawMessage) *rpcResponse {
+	if !json.Valid(raw) {
+		return rpcFailure(rpcNull, &rpcError{Code: rpcParseError, Message: "parse error"})
+	}
+
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil || !validRPCID(req.ID) {
+		return rpcFailure(rpcNull, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"})
+	}
+	if req.JSONRPC != jsonRPCVersion || req.Method == "" {
+		id := req.ID
+		if id == nil {
+			id = rpcNull
+		}
+		return rpcFailure(id, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"})
+	}
+
+	result, err := ws.callMethod(ctx, req)
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		return rpcFailure(req.ID, rpcErrorFrom(err))
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("Failed to encode JSON-RPC result: %v", err)
+		return rpcFailure(req.ID, &rpcError{Code: rpcInternalError, Message: "internal error"})
+	}
+	return &rpcResponse{JSONRPC: jsonRPCVersion, Result: encoded, ID: req.ID}
+}
+
+func (ws *WebServer) callMethod(ctx context.Context, req rpcRequest) (interface{}, error) {
+	clientID, _ := ctx.Value(session.ContextKeyClientID).(string)
+	logger.Infof("Handling JSON-RPC call from client %s: %s", clientID, req.Method)
+
+	action, ok := rpcMethods[req.Method]
+	if !ok {
+		return nil, errMethodNotFound
+	}
+	// apenas parâmetros nomeados
+	params := bytes.TrimSpace(req.Params)
+	if len(params) > 0 && params[0] != '{' && string(params) != "null" {
+		return nil, errInvalidParams
+	}
+	return ws.execute(ctx, WebSocketRequest{Action: action, Data: jsonPayload(params)})
+}
+
+// o id, quando presente, deve ser string, número ou null
+func validRPCID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	var v interface{}
+	if err := json.Unmarshal(id, &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case nil, string, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func rpcErrorFrom(err error) *rpcError {
+	switch {
+	case errors.Is(err, errMethodNotFound):
+		return &rpcError{Code: rpcMethodNotFound, Message: err.Error()}
+	case errors.Is(err, errInvalidParams), errors.Is(err, errInvalidRequest):
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+
+	wsErr := wsErrorFrom(err)
+	if wsErr.Code == WSErrInternal {
+		return &rpcError{Code: rpcInternalError, Message: wsErr.Message}
+	}
+	return &rpcError{Code: rpcServerError, Message: wsErr.Message, Data: wsErr}
+}
+
+func rpcFailure(id json.RawMessage, err *rpcError) *rpcResponse {
+	return &rpcResponse{JSONRPC: jsonRPCVersion, Error: err, ID: id}
+}
+
+var rpcPush = &hub.Encoding{
+	Transcode: eventToNotification,
+}
+
+// eventToNotification converte os eventos do servidor, sempre no formato v2
+// do protocolo WebSocket, em notificações JSON-RPC
+func eventToNotification(msg []byte) ([]byte, error) {
+	var event struct {
+		Action string          `json:"action"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return nil, err
+	}
+	return json.Marshal(rpcNotification{
+		JSONRPC: jsonRPCVersion,
+		Method:  rpcEventPrefix + event.Action,
+		Params:  event.Data,
+	})
+}
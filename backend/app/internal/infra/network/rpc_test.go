@@ -0,0 +1,219 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"game/api/internal/domain/entity"
+	"game/api/internal/errs"
+	"game/api/internal/infra/session"
+)
+
+// rpcContext simula uma chave de API só com o escopo de leitura da carteira:
+// as chamadas param antes de chegar aos controllers, que o teste não tem
+func rpcContext() context.Context {
+	ctx := context.WithValue(context.Background(), session.ContextKeyClientID, "client")
+	return context.WithValue(ctx, session.ContextKeyAPIKey, session.APIKeyPrincipal{
+		KeyID:    "key",
+		ClientID: "client",
+		Scopes:   []string{string(entity.ScopeWalletRead)},
+	})
+}
+
+// decodeRPC normaliza a resposta de handleRPC para uma lista
+func decodeRPC(t *testing.T, res interface{}) []rpcResponse {
+	t.Helper()
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var responses []rpcResponse
+	if strings.HasPrefix(string(data), "[") {
+		err = json.Unmarshal(data, &responses)
+	} else {
+		var single rpcResponse
+		err = json.Unmarshal(data, &single)
+		responses = append(responses, single)
+	}
+	if err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+	return responses
+}
+
+// errorCodes resume cada resposta como "id:código"
+func errorCodes(responses []rpcResponse) []string {
+	codes := make([]string, 0, len(responses))
+	for _, res := range responses {
+		code := 0
+		if res.Error != nil {
+			code = res.Error.Code
+		}
+		codes = append(codes, fmt.Sprintf("%s:%d", res.ID, code))
+	}
+	return codes
+}
+
+func TestHandleRPC(t *testing.T) {
+	ws := newDocsServer(t)
+
+	tooLarge := make([]string, maxRPCBatch+1)
+	for i := range tooLarge {
+		tooLarge[i] = fmt.Sprintf(`{"jsonrpc":"2.0","method":"wallet.get","id":%d}`, i)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		batch  bool
+		want   []string
+		noResp bool
+	}{
+		{"parse error", `{"jsonrpc":`, false, []string{"null:-32700"}, false},
+		{"empty body", ``, false, []string{"null:-32700"}, false},
+		{"wrong version", `{"jsonrpc":"1.0","method":"wallet.get","id":1}`, false, []string{"1:-32600"}, false},
+		{"missing method", `{"jsonrpc":"2.0","id":"a"}`, false, []string{`"a":-32600`}, false},
+		{"object id", `{"jsonrpc":"2.0","method":"wallet.get","id":{}}`, false, []string{"null:-32600"}, false},
+		{"not an object", `1`, false, []string{"null:-32600"}, false},
+		{"method not found", `{"jsonrpc":"2.0","method":"match.unknown","id":1}`, false, []string{"1:-32601"}, false},
+		{"positional params", `{"jsonrpc":"2.0","method":"match.bet","params":[10],"id":1}`, false, []string{"1:-32602"}, false},
+		{"out of scope", `{"jsonrpc":"2.0","method":"match.new","id":1}`, false, []string{"1:-32000"}, false},
+		{"null id is answered", `{"jsonrpc":"2.0","method":"match.unknown","id":null}`, false, []string{"null:-32601"}, false},
+		{"notification", `{"jsonrpc":"2.0","method":"match.new"}`, false, nil, true},
+		{"failed notification", `{"jsonrpc":"2.0","method":"match.unknown"}`, false, nil, true},
+		{"invalid batch", `[{"jsonrpc":"2.0"`, false, []string{"null:-32700"}, false},
+		{"empty batch", `[]`, false, []string{"null:-32600"}, false},
+		{"batch too large", "[" + strings.Join(tooLarge, ",") + "]", false, []string{"null:-32600"}, false},
+		{"batch of invalid entries", `[1,2]`, true, []string{"null:-32600", "null:-32600"}, false},
+		{"batch keeps order and skips notifications", `[
+			{"jsonrpc":"2.0","method":"match.unknown","id":1},
+			{"jsonrpc":"2.0","method":"match.new"},
+			{"jsonrpc":"2.0","method":"match.new","id":"b"},
+			{"jsonrpc":"2.0","id":3}
+		]`, true, []string{"1:-32601", `"b":-32000`, "3:-32600"}, false},
+		{"batch of notifications", `[{"jsonrpc":"2.0","method":"match.new"},{"jsonrpc":"2.0","method":"match.end"}]`, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok := ws.handleRPC(rpcContext(), []byte(tt.body))
+			if tt.noResp {
+				if ok {
+					t.Fatalf("handleRPC answered a notification: %v", res)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("handleRPC returned no response")
+			}
+			if _, isBatch := res.([]*rpcResponse); isBatch != tt.batch {
+				t.Errorf("batch response = %v, want %v", isBatch, tt.batch)
+			}
+			responses := decodeRPC(t, res)
+			got := errorCodes(responses)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("responses = %v, want %v", got, tt.want)
+			}
+			for _, r := range responses {
+				if r.JSONRPC != jsonRPCVersion || r.Result != nil {
+					t.Errorf("response %+v, want a 2.0 error without result", r)
+				}
+			}
+		})
+	}
+}
+
+func TestRPCServerErrorData(t *testing.T) {
+	ws := newDocsServer(t)
+	res, _ := ws.handleRPC(rpcContext(), []byte(`{"jsonrpc":"2.0","method":"match.bet","id":1}`))
+	responses := decodeRPC(t, res)
+
+	// erros de negócio trazem o código do protocolo WebSocket em data.code
+	data, err := json.Marshal(responses[0].Error.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wsErr WSError
+	if err := json.Unmarshal(data, &wsErr); err != nil {
+		t.Fatal(err)
+	}
+	if wsErr.Code != WSErrInsufficientScope {
+		t.Errorf("data.code = %q, want %q", wsErr.Code, WSErrInsufficientScope)
+	}
+}
+
+func TestRPCErrorFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantData string
+	}{
+		{"method not found", errMethodNotFound, rpcMethodNotFound, ""},
+		{"invalid params", errInvalidParams, rpcInvalidParams, ""},
+		{"invalid payload", fmt.Errorf("%w: invalid bet payload", errInvalidRequest), rpcInvalidParams, ""},
+		{"business error", errs.ErrInsufficientBalance, rpcServerError, WSErrInsufficientBalance},
+		{"wrapped business error", fmt.Errorf("bet: %w", errs.ErrPlayerNotInMatch), rpcServerError, WSErrNotInMatch},
+		{"rate limited", &errs.RetryError{Err: errs.ErrRateLimited, RetryAfter: time.Second}, rpcServerError, WSErrRateLimited},
+		{"unexpected error", errors.New("boom"), rpcInternalError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rpcErrorFrom(tt.err)
+			if got.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", got.Code, tt.wantCode)
+			}
+			wsErr, _ := got.Data.(*WSError)
+			switch {
+			case tt.wantData == "" && got.Data != nil:
+				t.Errorf("data = %v, want none", got.Data)
+			case tt.wantData != "" && (wsErr == nil || wsErr.Code != tt.wantData):
+				t.Errorf("data = %v, want code %q", got.Data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestRPCOverHTTP(t *testing.T) {
+	ws := newDocsServer(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"call", `{"jsonrpc":"2.0","method":"match.unknown","id":1}`, http.StatusOK},
+		{"notification", `{"jsonrpc":"2.0","method":"match.unknown"}`, http.StatusNoContent},
+		{"body too large", `"` + strings.Repeat("a", maxRPCBody) + `"`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body)).WithContext(rpcContext())
+			w := httptest.NewRecorder()
+			ws.rpc(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestEventToNotification(t *testing.T) {
+	got, err := eventToNotification([]byte(`{"action":"balance_updated","data":{"balance":10}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"jsonrpc":"2.0","method":"event.balance_updated","params":{"balance":10}}`
+	if string(got) != want {
+		t.Errorf("eventToNotification = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,183 @@
+package network
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/eventlog"
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/session"
+)
+
+const (
+	// também é o intervalo dos comentários de keep-alive, que mantêm proxies
+	// de empresas sem fechar a conexão ociosa
+	sseWait = 15 * time.Second
+	// intervalo de reconexão sugerido ao EventSource
+	sseRetry = 3 * time.Second
+
+	// enviado quando eventos posteriores ao Last-Event-ID já saíram do
+	// buffer; o cliente deve recarregar o estado (saldo, partida)
+	sseResync = "resync"
+)
+
+// sessionTag identifica a sessão de uma conexão no buffer de eventos sem
+// guardar o token.
+func sessionTag(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyTag identifica no buffer de eventos as conexões de uma chave de API
+func apiKeyTag(keyID string) string {
+	return sessionTag("api_key:" + keyID)
+}
+
+// streamEvents envia por Server-Sent Events os mesmos eventos que o
+// WebSocket, para redes que bloqueiam o upgrade. O cabeçalho Last-Event-ID,
+// enviado pelo EventSource ao reconectar, retoma do último evento recebido.
+func (ws *WebServer) streamEvents(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok || ws.eventLog == nil {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-ws.streamsClosed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	lastID, resync, err := ws.resumeFrom(r, clientID)
+	if err != nil {
+		logger.Errorf("Failed to resume event stream: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cursor, err := ws.eventLog.Follow(clientID, lastID)
+	if err != nil {
+		if errors.Is(err, eventlog.ErrBusy) {
+			w.Header().Set("Retry-After", fmt.Sprint(int(sseWait.Seconds())))
+			http.Error(w, "Too many event streams", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close()
+
+	var tag string
+	if token, _ := ctx.Value(session.ContextKeyToken).(string); token != "" {
+		tag = sessionTag(token)
+	} else if principal, ok := session.APIKeyFromContext(ctx); ok {
+		tag = apiKeyTag(principal.KeyID)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// desliga o buffer de proxies como o nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	if resync {
+		fmt.Fprintf(w, "id: %s\nevent: %s\ndata: {}\n\n", lastID, sseResync)
+	}
+	flusher.Flush()
+
+	logger.Infof("Event stream opened for client %s", clientID)
+	defer logger.Infof("Event stream closed for client %s", clientID)
+
+	for {
+		entries, err := cursor.Next(ctx, sseWait)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"client_id": clientID,
+			}).Errorf("Failed to read events: %v", err)
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		closing := false
+		for _, e := range entries {
+			if !e.ForSession(tag) {
+				continue
+			}
+			if err := writeSSE(w, e); err != nil {
+				logger.Errorf("Invalid buffered event %s: %v", e.ID, err)
+				continue
+			}
+			closing = closing || e.Close
+		}
+		flusher.Flush()
+		if closing {
+			return
+		}
+	}
+}
+
+// CloseStreams encerra os streams SSE abertos; http.Server.Shutdown não
+// interrompe handlers em andamento. Deve ser registrado com
+// RegisterOnShutdown.
+func (ws *WebServer) CloseStreams() {
+	ws.closeStreamsOnce.Do(func() {
+		close(ws.streamsClosed)
+	})
+}
+
+// resumeFrom retorna o ID a partir do qual enviar e se o cliente perdeu
+// eventos. Sem Last-Event-ID, só os eventos novos são enviados.
+func (ws *WebServer) resumeFrom(r *http.Request, clientID string) (lastID string, resync bool, err error) {
+	lastID = r.Header.Get("Last-Event-ID")
+	if lastID != "" {
+		missed, err := ws.eventLog.Missed(r.Context(), clientID, lastID)
+		if err == nil && !missed {
+			return lastID, false, nil
+		}
+		if err != nil && !errors.Is(err, eventlog.ErrInvalidID) {
+			return "", false, err
+		}
+		resync = true
+	}
+
+	lastID, err = ws.eventLog.Tail(r.Context(), clientID)
+	return lastID, resync, err
+}
+
+// writeSSE escreve a mensagem do evento ({action, data}) no formato SSE, com
+// o tipo do evento em "event" e o corpo em "data"
+func writeSSE(w http.ResponseWriter, e eventlog.Entry) error {
+	var msg struct {
+		Action string          `json:"action"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(e.Message, &msg); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, msg.Action, msg.Data)
+	return err
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"game/api/internal/application/dto"
+	"game/api/internal/errs"
+	"game/api/internal/infra/session"
+)
+
+func (ws *WebServer) loginMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, _ := r.Context().Value(session.ContextKeyToken).(string)
+	res, err := ws.authController.VerifyMFA(r.Context(), token, req.Code)
+	if err != nil {
+		ws.mfaError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) enrollMFA(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+
+	res, err := ws.authController.EnrollMFA(r.Context(), clientID)
+	if err != nil {
+		ws.mfaError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) enableMFA(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := ws.authController.EnableMFA(r.Context(), clientID, req.Code)
+	if err != nil {
+		ws.mfaError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (ws *WebServer) disableMFA(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := ws.authController.DisableMFA(ws.originContext(r), clientID, req.Code); err != nil {
+		ws.mfaError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) confirmMFA(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, _ := r.Context().Value(session.ContextKeyToken).(string)
+	if err := ws.authController.ConfirmMFA(ws.originContext(r), clientID, token, req.Code); err != nil {
+		ws.mfaError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ws *WebServer) regenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := ws.authController.RegenerateRecoveryCodes(ws.originContext(r), clientID, req.Code)
+	if err != nil {
+		ws.mfaError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// originContext leva o IP e o User-Agent da requisição para o controle de
+// tentativas
+func (ws *WebServer) originContext(r *http.Request) context.Context {
+	ctx := context.WithValue(r.Context(), session.ContextKeyIP, ws.sessionManager.ClientIP(r))
+	return context.WithValue(ctx, session.ContextKeyUserAgent, r.UserAgent())
+}
+
+func (ws *WebServer) mfaError(w http.ResponseWriter, err error) {
+	var retryErr *errs.RetryError
+	switch {
+	case errors.As(err, &retryErr):
+		w.Header().Set("Retry-After", strconv.Itoa(retryErr.Seconds()))
+		http.Error(w, "Too many attempts", http.StatusTooManyRequests)
+	case errors.Is(err, errs.ErrInvalidMFACode):
+		http.Error(w, "Invalid two-factor code", http.StatusUnauthorized)
+	case errors.Is(err, errs.ErrMFANotEnrolled):
+		http.Error(w, "Two-factor authentication not enrolled", http.StatusBadRequest)
+	case errors.Is(err, errs.ErrMFAAlreadyEnabled):
+		http.Error(w, "Two-factor authentication already enabled", http.StatusConflict)
+	case errors.Is(err, errs.ErrNotFound):
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
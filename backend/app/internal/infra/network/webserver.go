@@ -6,12 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"game/api/internal/application/controller"
 	"game/api/internal/application/dto"
+	"game/api/internal/domain/entity"
 	"game/api/internal/errs"
+	"game/api/internal/infra/eventlog"
+	"game/api/internal/infra/hub"
 	"game/api/internal/infra/logger"
+	"game/api/internal/infra/ratelimit"
 	"game/api/internal/infra/session"
 
 	"github.com/go-chi/chi/v5"
@@ -23,69 +29,165 @@ const (
 	ActionPlaceBet string = "place_bet"
 	ActionWallet   string = "wallet"
 	ActionEndMatch string = "end_match"
-	pingPeriod            = 30 * time.Second
-	pongWait              = 60 * time.Second
-	writeWait             = 10 * time.Second
 )
 
+// validade da confirmação do 2FA para as rotas de requireFreshMFA
+const freshMFAMaxAge = 15 * time.Minute
+
+var actionScopes = map[string]entity.APIKeyScope{
+	ActionNewMatch: entity.ScopeBetsPlace,
+	ActionPlaceBet: entity.ScopeBetsPlace,
+	ActionEndMatch: entity.ScopeBetsPlace,
+	ActionWallet:   entity.ScopeWalletRead,
+}
+
+// WSResponse responde a uma requisição (ID e Action iguais aos dela, também
+// em caso de erro) ou traz um evento enviado pelo servidor (sem ID).
 type WSResponse struct {
+	ID     string      `json:"id,omitempty"`
 	Action string      `json:"action"`
 	Data   interface{} `json:"data"`
-	Error  string      `json:"error,omitempty"`
+	Error  *WSError    `json:"error,omitempty"`
+	// posição no buffer de eventos do cliente, usada para retomar a conexão
+	// (ws_resume.go)
+	Seq string `json:"seq,omitempty"`
 }
 
+// WebSocketRequest é a requisição já separada do envelope pelo codec da
+// conexão; Data continua codificado até o handler saber o tipo da ação.
 type WebSocketRequest struct {
-	Action string      `json:"action"`
-	Data   interface{} `json:"data"`
+	// opcional, escolhido pelo cliente para correlacionar a resposta
+	ID     string
+	Action string
+	Data   Payload
+}
+
+// Payload decodifica o corpo da requisição no tipo da ação (ws_payloads.go)
+type Payload interface {
+	Bind(v interface{}) error
 }
 
-type Client struct {
-	Conn *websocket.Conn
-	Send chan []byte
+func (r WebSocketRequest) Bind(v interface{}) error {
+	if r.Data == nil {
+		return nil
+	}
+	if err := r.Data.Bind(v); err != nil {
+		return fmt.Errorf("%w: invalid %s payload: %v", errInvalidRequest, r.Action, err)
+	}
+	return nil
 }
 
 type WebServer struct {
 	*chi.Mux
-	clientController *controller.ClientController
-	authController   *controller.AuthController
-	matchController  *controller.MatchController
-	upgrader         websocket.Upgrader
-	clients          map[string]*Client
-	sessionManager   *session.Manager
+	clientController   *controller.ClientController
+	authController     *controller.AuthController
+	matchController    *controller.MatchController
+	passwordController *controller.PasswordController
+	adminController    *controller.AdminController
+	privacyController  *controller.PrivacyController
+	apiKeyController   *controller.APIKeyController
+	upgrader           websocket.Upgrader
+	hub                *hub.Hub
+	router             hub.Router
+	eventLog           *eventlog.Log
+	limiter            ratelimit.Store
+	limits             RateLimits
+	cors               CORSConfig
+	sessionManager     *session.Manager
+	streamsClosed      chan struct{}
+	closeStreamsOnce   sync.Once
 }
 
 func NewWebServer(
 	clientController *controller.ClientController,
 	authController *controller.AuthController,
 	matchController *controller.MatchController,
+	passwordController *controller.PasswordController,
+	adminController *controller.AdminController,
+	privacyController *controller.PrivacyController,
+	apiKeyController *controller.APIKeyController,
 	sessionManager *session.Manager,
+	connHub *hub.Hub,
+	router hub.Router,
+	eventLog *eventlog.Log,
+	limiter ratelimit.Store,
+	limits RateLimits,
+	cors CORSConfig,
 ) *WebServer {
 	ws := &WebServer{
-		Mux:              chi.NewMux(),
-		clientController: clientController,
-		authController:   authController,
-		matchController:  matchController,
-		sessionManager:   sessionManager,
+		Mux:                chi.NewMux(),
+		clientController:   clientController,
+		authController:     authController,
+		matchController:    matchController,
+		passwordController: passwordController,
+		adminController:    adminController,
+		privacyController:  privacyController,
+		apiKeyController:   apiKeyController,
+		sessionManager:     sessionManager,
+		hub:                connHub,
+		router:             router,
+		eventLog:           eventLog,
+		limiter:            limiter,
+		limits:             limits,
+		cors:               cors,
+		streamsClosed:      make(chan struct{}),
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
-			CheckOrigin: func(r *http.Request) bool {
-				return true
-			},
+			Subprotocols:    append(wsSubprotocols(), SubprotocolJSONRPC),
 		},
-		clients: make(map[string]*Client),
 	}
+	ws.upgrader.CheckOrigin = ws.checkOrigin
 
 	ws.setupRoutes()
 	return ws
 }
 
 func (ws *WebServer) setupRoutes() {
-	ws.Post("/register", ws.register)
-	ws.Post("/login", ws.login)
+	ws.Use(ws.corsMiddleware)
+	ws.Get("/.well-known/jwks.json", ws.jwks)
+	ws.With(ws.limitByIP(rateLimitRegister, ws.limits.Register)).Post("/register", ws.register)
+	ws.With(ws.limitByIP(rateLimitLogin, ws.limits.Login)).Post("/login", ws.login)
+	ws.With(ws.limitByIP(rateLimitLogin, ws.limits.Login)).Post("/login/2fa", ws.sessionManager.ValidatePartialJWT(ws.loginMFA))
 	ws.Post("/logout", ws.sessionManager.ValidateJWT(ws.logout))
-	ws.Get("/wallet", ws.sessionManager.ValidateJWT(ws.wallet))
-	ws.Get("/ws", ws.sessionManager.ValidateJWT(ws.handleWebSocket))
+	ws.Post("/session/reauth", ws.sessionManager.ValidateUnboundJWT(ws.reauth))
+	ws.Get("/email/verify", ws.verifyEmailForm)
+	ws.Post("/email/verify", ws.verifyEmail)
+	ws.Post("/email/resend", ws.sessionManager.ValidateJWT(ws.resendVerification))
+	ws.Put("/email", ws.sessionManager.ValidateJWT(ws.changeEmail))
+	ws.Post("/password/change", ws.sessionManager.ValidateJWT(ws.changePassword))
+	ws.With(ws.limitByIP(rateLimitPassword, ws.limits.PasswordReset)).Post("/password/forgot", ws.forgotPassword)
+	ws.Post("/password/reset", ws.resetPassword)
+	ws.Post("/2fa/enroll", ws.sessionManager.ValidateJWT(ws.enrollMFA))
+	ws.Post("/2fa/enable", ws.sessionManager.ValidateJWT(ws.enableMFA))
+	ws.Post("/2fa/disable", ws.sessionManager.ValidateJWT(ws.disableMFA))
+	ws.Post("/2fa/confirm", ws.sessionManager.ValidateJWT(ws.confirmMFA))
+	ws.Post("/2fa/recovery-codes", ws.sessionManager.ValidateJWT(ws.regenerateRecoveryCodes))
+	ws.Post("/account/close", ws.sessionManager.ValidateJWT(ws.requireFreshMFA(ws.closeAccount)))
+	ws.Get("/account/export", ws.sessionManager.ValidateJWT(ws.exportData))
+	ws.Post("/account/erase", ws.sessionManager.ValidateJWT(ws.requireFreshMFA(ws.eraseAccount)))
+	ws.Post("/api-keys", ws.sessionManager.ValidateJWT(ws.requireFreshMFA(ws.createAPIKey)))
+	ws.Get("/api-keys", ws.sessionManager.ValidateJWT(ws.listAPIKeys))
+	ws.Delete("/api-keys/{id}", ws.sessionManager.ValidateJWT(ws.revokeAPIKey))
+	ws.Get("/wallet", ws.sessionManager.ValidateJWTOrAPIKey(string(entity.ScopeWalletRead), ws.limitAction(ActionWallet, ws.wallet)))
+	ws.Get("/ws", ws.sessionManager.ValidateJWTOrAPIKey("", ws.handleWebSocket))
+	ws.Post("/rpc", ws.sessionManager.ValidateJWTOrAPIKey("", ws.rpc))
+	ws.Get("/events", ws.sessionManager.ValidateJWTOrAPIKey("", ws.streamEvents))
+	ws.setupMatchRoutes()
+	ws.setupAdminRoutes()
+	ws.setupDocs()
+}
+
+// requireFreshMFA protege ações irreversíveis ou que criam credenciais: com
+// 2FA habilitado, o código deve ter sido confirmado há pouco
+func (ws *WebServer) requireFreshMFA(next http.HandlerFunc) http.HandlerFunc {
+	return ws.sessionManager.RequireFreshMFA(freshMFAMaxAge, next)
+}
+
+func (ws *WebServer) jwks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(ws.sessionManager.JWKS())
 }
 
 func (ws *WebServer) register(w http.ResponseWriter, r *http.Request) {
@@ -95,13 +197,16 @@ func (ws *WebServer) register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := ws.clientController.Create(r.Context(), req.Username, req.Password)
+	res, err := ws.clientController.Create(r.Context(), req.Username, req.Email, req.Password)
 	if err != nil {
-		if err == errs.ErrUsernameExists {
-			http.Error(w, "Username already exists", http.StatusConflict)
-			return
+		switch {
+		case errors.Is(err, errs.ErrUsernameExists), errors.Is(err, errs.ErrEmailExists):
+			ws.validationError(w, http.StatusConflict, err)
+		case errors.Is(err, errs.ErrValidation):
+			ws.validationError(w, http.StatusUnprocessableEntity, err)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -109,6 +214,17 @@ func (ws *WebServer) register(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(res)
 }
 
+func (ws *WebServer) validationError(w http.ResponseWriter, status int, err error) {
+	res, ok := controller.ValidationErrorResponse(err)
+	if !ok {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
 func (ws *WebServer) login(w http.ResponseWriter, r *http.Request) {
 	var req dto.ClientLoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -116,22 +232,25 @@ func (ws *WebServer) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ip := r.RemoteAddr
-	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
-		ip = forwardedFor
-	}
+	ip := ws.sessionManager.ClientIP(r)
 	userAgent := r.UserAgent()
 
 	ctx := context.WithValue(r.Context(), session.ContextKeyIP, ip)
 	ctx = context.WithValue(ctx, session.ContextKeyUserAgent, userAgent)
 
-	token, err := ws.authController.Login(ctx, req.Username, req.Password)
+	res, err := ws.authController.Login(ctx, req.Username, req.Password)
 	if err != nil {
-		switch err {
-		case errs.ErrNotFound:
-			http.Error(w, "Client not found", http.StatusNotFound)
-		case errs.ErrInvalidPassword:
-			http.Error(w, "Invalid password", http.StatusUnauthorized)
+		var retryErr *errs.RetryError
+		switch {
+		case errors.As(err, &retryErr):
+			w.Header().Set("Retry-After", strconv.Itoa(retryErr.Seconds()))
+			http.Error(w, "Too many login attempts", http.StatusTooManyRequests)
+		case errors.Is(err, errs.ErrInvalidCredentials):
+			http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+		case errors.Is(err, errs.ErrAccountSuspended):
+			http.Error(w, "Account suspended", http.StatusForbidden)
+		case errors.Is(err, errs.ErrAccountClosed):
+			http.Error(w, "Account closed", http.StatusForbidden)
 		default:
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
@@ -139,7 +258,7 @@ func (ws *WebServer) login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"token": token})
+	json.NewEncoder(w).Encode(res)
 }
 
 func (ws *WebServer) logout(w http.ResponseWriter, r *http.Request) {
@@ -153,6 +272,38 @@ func (ws *WebServer) logout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (ws *WebServer) reauth(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
+	if !ok || clientID == "" {
+		http.Error(w, "client ID is required", http.StatusUnauthorized)
+		return
+	}
+	var req dto.ReauthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, _ := r.Context().Value(session.ContextKeyToken).(string)
+	err := ws.authController.Reauthenticate(ws.originContext(r), clientID, token, req.Password, req.Code)
+	if err != nil {
+		var retryErr *errs.RetryError
+		switch {
+		case errors.As(err, &retryErr):
+			w.Header().Set("Retry-After", strconv.Itoa(retryErr.Seconds()))
+			http.Error(w, "Too many attempts", http.StatusTooManyRequests)
+		case errors.Is(err, errs.ErrInvalidCredentials):
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+		case errors.Is(err, errs.ErrInvalidMFACode):
+			http.Error(w, "Invalid two-factor code", http.StatusUnauthorized)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (ws *WebServer) wallet(w http.ResponseWriter, r *http.Request) {
 	clientID, ok := r.Context().Value(session.ContextKeyClientID).(string)
 	if !ok || clientID == "" {
@@ -182,197 +333,209 @@ func (ws *WebServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := &Client{
-		Conn: conn,
-		Send: make(chan []byte, 256),
+	// vazio para conexões autenticadas por chave de API
+	token, _ := r.Context().Value(session.ContextKeyToken).(string)
+	var keyID string
+	if principal, ok := session.APIKeyFromContext(r.Context()); ok {
+		keyID = principal.KeyID
 	}
-
-	ws.clients[clientID] = client
+	client := ws.hub.Register(clientID, token, keyID, conn)
 
 	ctx := context.WithValue(r.Context(), session.ContextKeyClientID, clientID)
+	ctx = withConnLimiter(ctx)
 
-	go ws.handleConnection(ctx, conn)
+	if client.Subprotocol() == SubprotocolJSONRPC {
+		go ws.handleRPCConnection(ctx, client)
+		return
+	}
+	go ws.handleConnection(ctx, client)
 }
 
-func (ws *WebServer) handleConnection(ctx context.Context, conn *websocket.Conn) {
-	clientID, _ := ctx.Value(session.ContextKeyClientID).(string)
-	logger.Infof("New WebSocket connection established for client %s", clientID)
-
-	defer func() {
-		logger.Infof("Closing WebSocket connection for client %s", clientID)
-		conn.Close()
-		if clientID != "" {
-			delete(ws.clients, clientID)
-		}
-	}()
-
-	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(pongWait))
-		return nil
-	})
-
-	conn.SetPingHandler(func(string) error {
-		return conn.WriteControl(websocket.PongMessage, []byte{}, time.Now().Add(writeWait))
-	})
-
-	conn.SetReadDeadline(time.Now().Add(pongWait))
-
-	go func() {
-		pingCtx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
-		ticker := time.NewTicker(pingPeriod)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				if err := conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(writeWait)); err != nil {
-					logger.Errorf("Error sending ping: %v", err)
-					return
-				}
-			case <-pingCtx.Done():
-				return
+// handleConnection é o goroutine de leitura da conexão; as respostas são
+// enfileiradas e escritas pelo hub. A versão do protocolo vem do
+// subprotocolo do handshake e pode ser trocada por um hello como primeira
+// mensagem. Na v2 a conexão recebe um token para ser retomada depois de uma
+// queda (ws_resume.go).
+func (ws *WebServer) handleConnection(ctx context.Context, client *hub.Conn) {
+	logger.Infof("New WebSocket connection established for client %s", client.ClientID)
+	defer client.Close(websocket.CloseNormalClosure, "")
+
+	codec := codecForSubprotocol(client.Subprotocol())
+	client.SetEncoding(codec.PushEncoding())
+	var resume *wsResume
+	resumeOpened := false
+	for first := true; ; first = false {
+		if !resumeOpened && ws.supportsResume(codec) {
+			resumeOpened = true
+			var err error
+			if resume, err = ws.openResume(client); err != nil {
+				logger.Errorf("Failed to open WebSocket resume session: %v", err)
 			}
 		}
-	}()
 
-	for {
-		var request WebSocketRequest
-		err := conn.ReadJSON(&request)
+		msg, err := client.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure, websocket.CloseNormalClosure) {
 				logger.Errorf("Error reading message: %v", err)
 			}
 			break
 		}
 
-		conn.SetReadDeadline(time.Now().Add(pongWait))
+		var response *WSResponse
+		request, err := codec.Decode(msg)
+		switch {
+		case err != nil:
+			response = ws.errorResponse("", fmt.Errorf("%w: %v", errInvalidRequest, err))
+		case request.Action == ActionHello && !first:
+			response = ws.errorResponse(ActionHello, fmt.Errorf("%w: hello must be the first message", errInvalidRequest))
+		case request.Action == ActionHello:
+			response, codec = ws.hello(request, codec)
+			client.SetEncoding(codec.PushEncoding())
+		case request.Action == ActionResume:
+			response = ws.resume(client, request, resume)
+		default:
+			response = ws.handleRecorded(ctx, request, resume)
+		}
 
-		response := ws.handleRequest(ctx, request)
-		if err := conn.WriteJSON(response); err != nil {
-			logger.Errorf("Error writing response: %v", err)
+		data, err := codec.Encode(response)
+		if err != nil {
+			logger.Errorf("Failed to encode WebSocket response: %v", err)
+			break
+		}
+		if !client.SendEncoded(data, codec.Encoding() != EncodingJSON) {
 			break
 		}
 	}
 }
 
+// hello negocia a versão do protocolo; a resposta já sai na versão escolhida
+func (ws *WebServer) hello(request WebSocketRequest, current wsCodec) (*WSResponse, wsCodec) {
+	codec, err := ws.negotiate(request)
+	if err != nil {
+		response := ws.errorResponse(ActionHello, err)
+		response.ID = request.ID
+		return response, current
+	}
+
+	response := ws.successResponse(ActionHello, helloResponse{
+		Version:      codec.Version(),
+		Encoding:     codec.Encoding(),
+		Versions:     supportedVersions(),
+		Encodings:    supportedEncodings(),
+		Capabilities: codec.Capabilities(),
+	})
+	response.ID = request.ID
+	return response, codec
+}
+
 func (ws *WebServer) handleRequest(ctx context.Context, request WebSocketRequest) *WSResponse {
 	clientID, _ := ctx.Value(session.ContextKeyClientID).(string)
 	logger.Infof("Handling request from client %s: %s", clientID, request.Action)
 
-	msgCtx := context.Background()
-	msgCtx = context.WithValue(msgCtx, session.ContextKeyClientID, clientID)
-
-	msgCtx, cancel := context.WithTimeout(msgCtx, 1000*time.Second)
+	msgCtx, cancel := messageContext(ctx)
 	defer cancel()
 
-	var response *WSResponse
-
-	switch request.Action {
-	case ActionNewMatch:
-		response = ws.handleNewMatch(msgCtx)
-	case ActionPlaceBet:
-		response = ws.handleBet(msgCtx, request.Data)
-	case ActionWallet:
-		response = ws.handleWallet(msgCtx)
-	case ActionEndMatch:
-		response = ws.handleEndMatch(msgCtx)
-	default:
-		logger.Errorf("Invalid action from client %s: %s", clientID, request.Action)
-		response = ws.errorResponse("Invalid action")
+	data, err := ws.execute(msgCtx, request)
+	response := ws.successResponse(request.Action, data)
+	if err != nil {
+		response = ws.errorResponse(request.Action, err)
 	}
-
+	response.ID = request.ID
 	return response
 }
 
-func (ws *WebServer) handleNewMatch(ctx context.Context) *WSResponse {
-	clientID, ok := ctx.Value(session.ContextKeyClientID).(string)
-	if !ok || clientID == "" {
-		return ws.errorResponse("client ID is required")
+// messageContext desvincula cada mensagem do contexto da conexão, mantendo só
+// o cliente, a chave de API e os limites da conexão
+func messageContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	clientID, _ := ctx.Value(session.ContextKeyClientID).(string)
+	msgCtx := context.WithValue(context.Background(), session.ContextKeyClientID, clientID)
+	if principal, ok := session.APIKeyFromContext(ctx); ok {
+		msgCtx = context.WithValue(msgCtx, session.ContextKeyAPIKey, principal)
 	}
-
-	err := ws.matchController.NewMatch(ctx, clientID)
-	if err != nil {
-		logger.Errorf("Failed to new match: %v", err)
-		return ws.errorResponse(err.Error())
+	if limiter := ctx.Value(contextKeyConnLimiter); limiter != nil {
+		msgCtx = context.WithValue(msgCtx, contextKeyConnLimiter, limiter)
 	}
-	return ws.successResponse(ActionNewMatch, nil)
+	return context.WithTimeout(msgCtx, 1000*time.Second)
 }
 
-func (ws *WebServer) handleBet(ctx context.Context, body interface{}) *WSResponse {
-	var req struct {
-		Amount float64 `json:"amount"`
-		Choice string  `json:"choice"`
+// execute é comum ao protocolo WebSocket e ao JSON-RPC. Clientes autenticados
+// com chave de API só executam ações dos seus escopos, e cada mensagem conta
+// para o limite da chave.
+func (ws *WebServer) execute(ctx context.Context, request WebSocketRequest) (interface{}, error) {
+	if scope, ok := actionScopes[request.Action]; ok && !session.HasScope(ctx, string(scope)) {
+		return nil, errInsufficientScope
 	}
-	if err := ws.unmarshalRequest(body, &req); err != nil {
-		logger.Errorf("Error unmarshaling bet request: %v", err)
-		return ws.errorResponse(err.Error())
+	if err := ws.allowAction(ctx, request.Action); err != nil {
+		return nil, err
 	}
+	if err := ws.sessionManager.ConsumeAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	return ws.dispatch(ctx, request)
+}
 
+func (ws *WebServer) dispatch(ctx context.Context, request WebSocketRequest) (interface{}, error) {
 	clientID, ok := ctx.Value(session.ContextKeyClientID).(string)
 	if !ok || clientID == "" {
 		logger.Errorf("Invalid client ID in context")
-		return ws.errorResponse("client ID is required")
+		return nil, errMissingClientID
 	}
 
-	result, err := ws.matchController.Bet(ctx, clientID, req.Amount, req.Choice)
-	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			logger.Errorf("Bet operation canceled for client %s: %v", clientID, err)
-			return ws.errorResponse("operation timed out, please try again")
-		}
-		logger.Errorf("Error placing bet for client %s: %v", clientID, err)
-		return ws.errorResponse(err.Error())
+	switch request.Action {
+	case ActionNewMatch:
+		return ws.handleNewMatch(ctx, clientID)
+	case ActionPlaceBet:
+		return ws.handleBet(ctx, clientID, request)
+	case ActionWallet:
+		return ws.handleWallet(ctx, clientID)
+	case ActionEndMatch:
+		return nil, ws.handleEndMatch(ctx, clientID)
+	default:
+		logger.Errorf("Invalid action from client %s: %s", clientID, request.Action)
+		return nil, errUnknownAction
 	}
-
-	return ws.successResponse(ActionPlaceBet, result)
 }
 
-func (ws *WebServer) handleWallet(ctx context.Context) *WSResponse {
-	clientID, ok := ctx.Value(session.ContextKeyClientID).(string)
-	if !ok || clientID == "" {
-		return ws.errorResponse("client ID is required")
-	}
-
-	wallet, err := ws.clientController.GetBalance(ctx, clientID)
+func (ws *WebServer) handleNewMatch(ctx context.Context, clientID string) (interface{}, error) {
+	match, err := ws.matchController.NewMatch(ctx, clientID)
 	if err != nil {
-		return ws.errorResponse(err.Error())
+		logger.Errorf("Failed to new match: %v", err)
+		return nil, err
 	}
-
-	return ws.successResponse(ActionWallet, wallet)
+	return match, nil
 }
 
-func (ws *WebServer) handleEndMatch(ctx context.Context) *WSResponse {
-	clientID, ok := ctx.Value(session.ContextKeyClientID).(string)
-	if !ok || clientID == "" {
-		return ws.errorResponse("client ID is required")
+func (ws *WebServer) handleBet(ctx context.Context, clientID string, request WebSocketRequest) (interface{}, error) {
+	var req PlaceBetPayload
+	if err := request.Bind(&req); err != nil {
+		logger.Errorf("Error unmarshaling bet request: %v", err)
+		return nil, err
 	}
 
-	err := ws.matchController.EndMatch(ctx, clientID)
+	result, err := ws.matchController.Bet(ctx, clientID, req.Amount, req.Choice)
 	if err != nil {
-		return ws.errorResponse(err.Error())
+		if errors.Is(err, context.Canceled) {
+			logger.Errorf("Bet operation canceled for client %s: %v", clientID, err)
+		} else {
+			logger.Errorf("Error placing bet for client %s: %v", clientID, err)
+		}
+		return nil, err
 	}
-	return ws.successResponse(ActionEndMatch, nil)
+	return result, nil
 }
 
-func (ws *WebServer) unmarshalRequest(body interface{}, req interface{}) error {
-	data, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	if err := json.Unmarshal(data, req); err != nil {
-		return fmt.Errorf("failed to unmarshal request body: %v", err)
-	}
+func (ws *WebServer) handleWallet(ctx context.Context, clientID string) (interface{}, error) {
+	return ws.clientController.GetBalance(ctx, clientID)
+}
 
-	return nil
+func (ws *WebServer) handleEndMatch(ctx context.Context, clientID string) error {
+	return ws.matchController.EndMatch(ctx, clientID)
 }
 
-func (ws *WebServer) errorResponse(msg string) *WSResponse {
+func (ws *WebServer) errorResponse(action string, err error) *WSResponse {
 	return &WSResponse{
-		Action: "error",
-		Error:  msg,
+		Action: action,
+		Error:  wsErrorFrom(err),
 	}
 }
 
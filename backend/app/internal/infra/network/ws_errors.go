@@ -0,0 +1,88 @@
+package network
+
+import (
+	"context"
+	"errors"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/eventlog"
+	"game/api/internal/infra/logger"
+)
+
+// Códigos de erro do protocolo WebSocket. São estáveis: clientes devem
+// decidir pelo código, não pela mensagem.
+const (
+	WSErrInvalidRequest      = "invalid_request"
+	WSErrUnknownAction       = "unknown_action"
+	WSErrUnauthorized        = "unauthorized"
+	WSErrInsufficientScope   = "insufficient_scope"
+	WSErrRateLimited         = "rate_limited"
+	WSErrValidation          = "validation_failed"
+	WSErrEmailNotVerified    = "email_not_verified"
+	WSErrAccountSuspended    = "account_suspended"
+	WSErrInsufficientBalance = "insufficient_balance"
+	WSErrAlreadyInMatch      = "already_in_match"
+	WSErrNotInMatch          = "not_in_match"
+	WSErrTimeout             = "timeout"
+	WSErrResumeExpired       = "resume_expired"
+	WSErrInternal            = "internal_error"
+)
+
+var (
+	errInvalidRequest    = errors.New("invalid request")
+	errUnknownAction     = errors.New("unknown action")
+	errInsufficientScope = errors.New("insufficient scope")
+	errMissingClientID   = errors.New("client ID is required")
+)
+
+type WSError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// segundos até a ação poder ser repetida, em rate_limited
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// wsErrorCodes é percorrido em ordem; a mensagem é a do erro sentinela
+var wsErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{errUnknownAction, WSErrUnknownAction},
+	{errMissingClientID, WSErrUnauthorized},
+	{errInsufficientScope, WSErrInsufficientScope},
+	{errs.ErrEmailNotVerified, WSErrEmailNotVerified},
+	{errs.ErrAccountSuspended, WSErrAccountSuspended},
+	{errs.ErrInsufficientBalance, WSErrInsufficientBalance},
+	{errs.ErrPlayerAlreadyInMatch, WSErrAlreadyInMatch},
+	{errs.ErrPlayerNotInMatch, WSErrNotInMatch},
+	{eventlog.ErrSessionNotFound, WSErrResumeExpired},
+}
+
+func wsErrorFrom(err error) *WSError {
+	var retryErr *errs.RetryError
+	if errors.As(err, &retryErr) {
+		return &WSError{
+			Code:       WSErrRateLimited,
+			Message:    retryErr.Error(),
+			RetryAfter: retryErr.Seconds(),
+		}
+	}
+	// a mensagem detalha o problema do corpo ou dos campos enviados
+	if errors.Is(err, errInvalidRequest) {
+		return &WSError{Code: WSErrInvalidRequest, Message: err.Error()}
+	}
+	if errors.Is(err, errs.ErrValidation) {
+		return &WSError{Code: WSErrValidation, Message: err.Error()}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &WSError{Code: WSErrTimeout, Message: "operation timed out, please try again"}
+	}
+	for _, c := range wsErrorCodes {
+		if errors.Is(err, c.err) {
+			return &WSError{Code: c.code, Message: c.err.Error()}
+		}
+	}
+
+	logger.Errorf("Unhandled WebSocket error: %v", err)
+	return &WSError{Code: WSErrInternal, Message: "internal server error"}
+}
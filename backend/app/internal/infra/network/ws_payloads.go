@@ -0,0 +1,23 @@
+package network
+
+// Corpos das requisições WebSocket por ação. As tags json também nomeiam os
+// campos em msgpack.
+
+type HelloPayload struct {
+	// versões aceitas pelo cliente, em ordem de preferência
+	Versions []int `json:"versions"`
+	// json (padrão) ou msgpack
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type ResumePayload struct {
+	// recebido na mensagem session da conexão anterior
+	ResumeToken string `json:"resume_token"`
+	// último seq recebido
+	Seq string `json:"seq"`
+}
+
+type PlaceBetPayload struct {
+	Amount float64 `json:"amount"`
+	Choice string  `json:"choice"`
+}
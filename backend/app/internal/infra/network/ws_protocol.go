@@ -0,0 +1,293 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"game/api/internal/infra/hub"
+)
+
+const (
+	ActionHello string = "hello"
+
+	// ProtocolV1 é o formato original, usado pelo frontend: sem id e com
+	// erros em {"action": "error", "error": "mensagem"}
+	ProtocolV1 = 1
+	// ProtocolV2 ecoa o id da requisição e retorna erros estruturados
+	ProtocolV2 = 2
+
+	EncodingJSON = "json"
+	// EncodingMsgpack usa frames binários com os mesmos nomes de campo do JSON
+	EncodingMsgpack = "msgpack"
+
+	subprotocolPrefix = "game.v"
+
+	CapabilityEvents           = "events"
+	CapabilityRequestID        = "request_id"
+	CapabilityStructuredErrors = "structured_errors"
+	CapabilityResume           = "resume"
+)
+
+// wsCodec traduz entre o formato de uma versão/codificação do protocolo e as
+// estruturas internas. Novas versões entram em wsCodecs sem alterar os
+// handlers.
+type wsCodec interface {
+	Version() int
+	Encoding() string
+	Capabilities() []string
+	Decode(data []byte) (WebSocketRequest, error)
+	Encode(res *WSResponse) ([]byte, error)
+	// conversão das mensagens enviadas pelo servidor (JSON v2); nil mantém o
+	// JSON
+	PushEncoding() *hub.Encoding
+}
+
+// em ordem de preferência do servidor
+var wsCodecs = []wsCodec{
+	v2MsgpackCodec{},
+	v2Codec{},
+	v1Codec{},
+}
+
+// versão assumida quando o cliente não negocia, para não quebrar clientes
+// antigos
+var defaultCodec wsCodec = v1Codec{}
+
+// subprotocolName identifica o codec no header Sec-WebSocket-Protocol:
+// game.v1, game.v2 e game.v2.msgpack
+func subprotocolName(c wsCodec) string {
+	name := fmt.Sprintf("%s%d", subprotocolPrefix, c.Version())
+	if c.Encoding() != EncodingJSON {
+		name += "." + c.Encoding()
+	}
+	return name
+}
+
+func wsSubprotocols() []string {
+	names := make([]string, 0, len(wsCodecs))
+	for _, c := range wsCodecs {
+		names = append(names, subprotocolName(c))
+	}
+	return names
+}
+
+// codecForSubprotocol retorna o codec do subprotocolo aceito no handshake
+// (vazio se o cliente não pediu nenhum).
+func codecForSubprotocol(subprotocol string) wsCodec {
+	for _, c := range wsCodecs {
+		if subprotocolName(c) == subprotocol {
+			return c
+		}
+	}
+	return defaultCodec
+}
+
+func findCodec(version int, encoding string) (wsCodec, bool) {
+	if encoding == "" {
+		encoding = EncodingJSON
+	}
+	for _, c := range wsCodecs {
+		if c.Version() == version && c.Encoding() == encoding {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func supportedVersions() []int {
+	return []int{ProtocolV2, ProtocolV1}
+}
+
+func supportedEncodings() []string {
+	return []string{EncodingJSON, EncodingMsgpack}
+}
+
+type helloResponse struct {
+	Version      int      `json:"version"`
+	Encoding     string   `json:"encoding"`
+	Versions     []int    `json:"versions"`
+	Encodings    []string `json:"encodings"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// negotiate escolhe a primeira versão do cliente que o servidor suporta na
+// codificação pedida
+func (ws *WebServer) negotiate(request WebSocketRequest) (wsCodec, error) {
+	var hello HelloPayload
+	if err := request.Bind(&hello); err != nil {
+		return nil, err
+	}
+	for _, v := range hello.Versions {
+		if codec, ok := findCodec(v, hello.Encoding); ok {
+			return codec, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no supported protocol version in %v with encoding %q, server supports versions %v and encodings %v",
+		errInvalidRequest, hello.Versions, hello.Encoding, supportedVersions(), supportedEncodings())
+}
+
+// wireRequest é o envelope de requisição comum a todas as versões; o corpo
+// fica codificado até o handler saber o tipo da ação
+type wireRequest struct {
+	ID     string          `json:"id,omitempty"`
+	Action string          `json:"action"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+type jsonPayload json.RawMessage
+
+func (p jsonPayload) Bind(v interface{}) error {
+	if len(p) == 0 || string(p) == "null" {
+		return nil
+	}
+	return json.Unmarshal(p, v)
+}
+
+func decodeJSONRequest(data []byte) (WebSocketRequest, error) {
+	var req wireRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return WebSocketRequest{}, err
+	}
+	return WebSocketRequest{ID: req.ID, Action: req.Action, Data: jsonPayload(req.Data)}, nil
+}
+
+type v1Codec struct{}
+
+type v1Response struct {
+	Action string      `json:"action"`
+	Data   interface{} `json:"data"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func (v1Codec) Version() int {
+	return ProtocolV1
+}
+
+func (v1Codec) Encoding() string {
+	return EncodingJSON
+}
+
+func (v1Codec) PushEncoding() *hub.Encoding {
+	return nil
+}
+
+func (v1Codec) Capabilities() []string {
+	return []string{CapabilityEvents}
+}
+
+// o id é lido, mas nunca volta na resposta
+func (v1Codec) Decode(data []byte) (WebSocketRequest, error) {
+	return decodeJSONRequest(data)
+}
+
+func (v1Codec) Encode(res *WSResponse) ([]byte, error) {
+	if res.Error != nil {
+		return json.Marshal(v1Response{Action: "error", Error: res.Error.Message})
+	}
+	return json.Marshal(v1Response{Action: res.Action, Data: res.Data})
+}
+
+type v2Codec struct{}
+
+func (v2Codec) Version() int {
+	return ProtocolV2
+}
+
+func (v2Codec) Encoding() string {
+	return EncodingJSON
+}
+
+func (v2Codec) PushEncoding() *hub.Encoding {
+	return nil
+}
+
+func (v2Codec) Capabilities() []string {
+	return []string{CapabilityEvents, CapabilityRequestID, CapabilityStructuredErrors, CapabilityResume}
+}
+
+func (v2Codec) Decode(data []byte) (WebSocketRequest, error) {
+	return decodeJSONRequest(data)
+}
+
+func (v2Codec) Encode(res *WSResponse) ([]byte, error) {
+	return json.Marshal(res)
+}
+
+// v2MsgpackCodec tem a mesma estrutura da v2 em JSON, usando as tags json
+// como nomes de campo
+type v2MsgpackCodec struct{}
+
+type msgpackWireRequest struct {
+	ID     string             `json:"id,omitempty"`
+	Action string             `json:"action"`
+	Data   msgpack.RawMessage `json:"data,omitempty"`
+}
+
+type msgpackPayload msgpack.RawMessage
+
+func (p msgpackPayload) Bind(v interface{}) error {
+	if len(p) == 0 {
+		return nil
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(p))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+var msgpackPush = &hub.Encoding{
+	Binary:    true,
+	Transcode: jsonToMsgpack,
+}
+
+func (v2MsgpackCodec) Version() int {
+	return ProtocolV2
+}
+
+func (v2MsgpackCodec) Encoding() string {
+	return EncodingMsgpack
+}
+
+func (v2MsgpackCodec) PushEncoding() *hub.Encoding {
+	return msgpackPush
+}
+
+func (v2MsgpackCodec) Capabilities() []string {
+	return v2Codec{}.Capabilities()
+}
+
+func (v2MsgpackCodec) Decode(data []byte) (WebSocketRequest, error) {
+	var req msgpackWireRequest
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(&req); err != nil {
+		return WebSocketRequest{}, err
+	}
+	return WebSocketRequest{ID: req.ID, Action: req.Action, Data: msgpackPayload(req.Data)}, nil
+}
+
+func (v2MsgpackCodec) Encode(res *WSResponse) ([]byte, error) {
+	return marshalMsgpack(res)
+}
+
+func marshalMsgpack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonToMsgpack converte eventos do servidor, que trafegam em JSON pelo
+// backplane, para conexões msgpack
+func jsonToMsgpack(msg []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(msg, &v); err != nil {
+		return nil, err
+	}
+	return marshalMsgpack(v)
+}
@@ -0,0 +1,241 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/eventlog"
+	"game/api/internal/infra/hub"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	ActionSession string = "session"
+	ActionResume  string = "resume"
+
+	// quanto a retomada espera pelas requisições da conexão anterior que
+	// ainda estão em andamento
+	resumeWait = 5 * time.Second
+	// operações no buffer fora da retomada
+	resumeTimeout = 2 * time.Second
+)
+
+// recordedActions alteram o estado do jogador, então uma resposta perdida
+// deixaria o cliente sem saber o resultado. As demais (como wallet) podem ser
+// repetidas e não ocupam o buffer, que também alimenta o SSE.
+var recordedActions = map[string]bool{
+	ActionNewMatch: true,
+	ActionPlaceBet: true,
+	ActionEndMatch: true,
+}
+
+// sessionResponse é enviado ao abrir uma conexão v2
+type sessionResponse struct {
+	ResumeToken string `json:"resume_token"`
+	// último seq do buffer; as mensagens seguintes chegam por esta conexão
+	Seq string `json:"seq"`
+}
+
+type resumeResponse struct {
+	// passa a identificar esta conexão
+	ResumeToken string `json:"resume_token"`
+	Replayed    int    `json:"replayed"`
+	// mensagens posteriores ao seq informado já saíram do buffer; o cliente
+	// deve recarregar o saldo e a partida
+	Resync bool `json:"resync"`
+	// requisições da conexão anterior que não terminaram a tempo
+	Pending int `json:"pending"`
+}
+
+// wsResume é o estado de retomada de uma conexão v2. As respostas de
+// recordedActions são gravadas no buffer de eventos do cliente com a tag da
+// sessão.
+type wsResume struct {
+	session eventlog.Session
+	// último evento do buffer na abertura da conexão; os posteriores são
+	// entregues a ela ao vivo
+	openedAt string
+	// alguma requisição já foi atendida; a retomada deve vir antes
+	handled bool
+}
+
+func (ws *WebServer) supportsResume(codec wsCodec) bool {
+	if ws.eventLog == nil {
+		return false
+	}
+	for _, c := range codec.Capabilities() {
+		if c == CapabilityResume {
+			return true
+		}
+	}
+	return false
+}
+
+// openResume cria a sessão de retomada da conexão e envia o token ao cliente
+func (ws *WebServer) openResume(client *hub.Conn) (*wsResume, error) {
+	ctx, cancel := bufferContext()
+	defer cancel()
+
+	s, err := ws.eventLog.NewSession(ctx, client.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	openedAt, err := ws.eventLog.Tail(ctx, client.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := json.Marshal(ws.successResponse(ActionSession, sessionResponse{
+		ResumeToken: s.Token,
+		Seq:         openedAt,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	client.Send(msg)
+	return &wsResume{session: s, openedAt: openedAt}, nil
+}
+
+// handleRecorded grava a resposta no buffer antes de enviá-la, para que uma
+// resposta perdida com a queda da conexão possa ser reenviada na retomada
+func (ws *WebServer) handleRecorded(ctx context.Context, request WebSocketRequest, resume *wsResume) *WSResponse {
+	if resume == nil {
+		return ws.handleRequest(ctx, request)
+	}
+	resume.handled = true
+	if !recordedActions[request.Action] {
+		return ws.handleRequest(ctx, request)
+	}
+	s := resume.session
+
+	// a retomada espera as requisições em andamento gravarem a resposta
+	bufCtx, cancel := bufferContext()
+	err := ws.eventLog.Begin(bufCtx, s)
+	cancel()
+	tracked := err == nil
+	if err != nil {
+		logger.Errorf("Failed to track WebSocket request: %v", err)
+	}
+
+	response := ws.handleRequest(ctx, request)
+
+	bufCtx, cancel = bufferContext()
+	defer cancel()
+	seq, err := ws.recordResponse(bufCtx, s, response)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"client_id": s.ClientID,
+			"action":    request.Action,
+		}).Errorf("Failed to record WebSocket response: %v", err)
+	}
+	response.Seq = seq
+	if tracked {
+		ws.eventLog.End(bufCtx, s)
+	}
+	return response
+}
+
+func (ws *WebServer) recordResponse(ctx context.Context, s eventlog.Session, response *WSResponse) (string, error) {
+	msg, err := json.Marshal(response)
+	if err != nil {
+		return "", err
+	}
+	return ws.eventLog.Append(ctx, s.ClientID, eventlog.Entry{
+		Message:  msg,
+		Sessions: []string{s.Tag},
+	})
+}
+
+// resume reenvia as mensagens perdidas pela conexão anterior desde o seq
+// informado: as respostas dela e os eventos anteriores à abertura desta
+// conexão. Depois disso a conexão assume a sessão anterior.
+func (ws *WebServer) resume(client *hub.Conn, request WebSocketRequest, current *wsResume) *WSResponse {
+	res, err := ws.replay(client, request, current)
+	response := ws.successResponse(ActionResume, res)
+	if err != nil {
+		response = ws.errorResponse(ActionResume, err)
+	}
+	response.ID = request.ID
+	return response
+}
+
+func (ws *WebServer) replay(client *hub.Conn, request WebSocketRequest, current *wsResume) (*resumeResponse, error) {
+	switch {
+	case current == nil:
+		return nil, fmt.Errorf("%w: resume is not available on this connection", errInvalidRequest)
+	case current.handled:
+		return nil, fmt.Errorf("%w: resume must precede other requests", errInvalidRequest)
+	}
+	var req ResumePayload
+	if err := request.Bind(&req); err != nil {
+		return nil, err
+	}
+	if req.ResumeToken == "" || req.Seq == "" {
+		return nil, fmt.Errorf("%w: resume_token and seq are required", errInvalidRequest)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resumeWait+resumeTimeout)
+	defer cancel()
+
+	previous, err := ws.eventLog.ResumeSession(ctx, client.ClientID, req.ResumeToken)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := ws.eventLog.WaitIdle(ctx, previous, resumeWait)
+	if err != nil {
+		return nil, err
+	}
+	missed, err := ws.eventLog.Missed(ctx, client.ClientID, req.Seq)
+	if errors.Is(err, eventlog.ErrInvalidID) {
+		return nil, fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ws.eventLog.Since(ctx, client.ClientID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &resumeResponse{ResumeToken: previous.Token, Resync: missed, Pending: pending}
+	for _, e := range entries {
+		// eventos para todo o cliente posteriores à abertura já chegaram ao
+		// vivo; revogações de sessão não são reenviadas
+		if !e.ForSession(previous.Tag) || (e.Sessions == nil && eventlog.After(e.ID, current.openedAt)) {
+			continue
+		}
+		msg, err := withSeq(e.Message, e.ID)
+		if err != nil {
+			logger.Errorf("Invalid buffered message %s: %v", e.ID, err)
+			continue
+		}
+		if !client.Send(msg) {
+			break
+		}
+		res.Replayed++
+	}
+
+	current.session = previous
+	current.handled = true
+	return res, nil
+}
+
+// bufferContext limita as operações no buffer, que não dependem da conexão
+func bufferContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), resumeTimeout)
+}
+
+// withSeq inclui o ID do buffer em uma mensagem gravada sem ele
+func withSeq(msg []byte, seq string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return nil, err
+	}
+	fields["seq"], _ = json.Marshal(seq)
+	return json.Marshal(fields)
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/logger"
+	"game/api/internal/infra/mail"
+)
+
+type Notification struct {
+	ClientID string    `json:"client_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email,omitempty"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// Notifier entrega mensagens ao cliente (e-mail, SMS, ...). As implementações
+// locais abaixo servem apenas para desenvolvimento.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (l *LogNotifier) Notify(ctx context.Context, n Notification) error {
+	logger.WithFields(logrus.Fields{
+		"client_id": n.ClientID,
+		"username":  n.Username,
+		"subject":   n.Subject,
+	}).Info(n.Body)
+	return nil
+}
+
+// FileNotifier grava cada notificação como uma linha JSON no arquivo informado
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{
+		path: path,
+	}
+}
+
+func (f *FileNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.SentAt.IsZero() {
+		n.SentAt = time.Now()
+	}
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		logger.Errorf("Failed to open notification file: %v", err)
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// MailNotifier entrega a notificação por e-mail quando o cliente tem um
+// endereço cadastrado
+type MailNotifier struct {
+	transport mail.Transport
+}
+
+func NewMailNotifier(transport mail.Transport) *MailNotifier {
+	return &MailNotifier{
+		transport: transport,
+	}
+}
+
+func (m *MailNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		logger.WithFields(logrus.Fields{
+			"client_id": n.ClientID,
+			"subject":   n.Subject,
+		}).Warn("Notification dropped: client has no email address")
+		return nil
+	}
+	return m.transport.Send(ctx, mail.Message{
+		To:      n.Email,
+		Subject: n.Subject,
+		Body:    n.Body,
+	})
+}
@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock é um relógio manual para os testes do Memory
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestMemory() (*Memory, *clock) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+	m := NewMemory()
+	m.now = c.Now
+	m.lastSweep = c.now
+	return m, c
+}
+
+func TestMemoryTakeRefill(t *testing.T) {
+	// 4 fichas por segundo: uma a cada 250ms
+	limit := Limit{Burst: 4, Period: time.Second}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    time.Duration
+	}{
+		{"burst 1", 0, 0},
+		{"burst 2", 0, 0},
+		{"burst 3", 0, 0},
+		{"burst 4", 0, 0},
+		{"empty", 0, 250 * time.Millisecond},
+		{"still empty, partial refill", 100 * time.Millisecond, 150 * time.Millisecond},
+		{"one token refilled", 150 * time.Millisecond, 0},
+		{"empty again", 0, 250 * time.Millisecond},
+		{"refill is capped at burst", time.Hour, 0},
+		{"burst after cap 2", 0, 0},
+		{"burst after cap 3", 0, 0},
+		{"burst after cap 4", 0, 0},
+		{"empty after cap", 0, 250 * time.Millisecond},
+	}
+
+	ctx := context.Background()
+	m, c := newTestMemory()
+	for _, tt := range tests {
+		c.Advance(tt.advance)
+		got, err := m.Take(ctx, "key", limit)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: Take = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryRefusedTakeDoesNotConsume(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Burst: 1, Period: time.Second}
+	m, c := newTestMemory()
+
+	m.Take(ctx, "key", limit)
+	for i := 0; i < 5; i++ {
+		if wait, _ := m.Take(ctx, "key", limit); wait != time.Second {
+			t.Fatalf("refused Take %d = %s, want 1s", i+1, wait)
+		}
+	}
+	c.Advance(time.Second)
+	if wait, _ := m.Take(ctx, "key", limit); wait != 0 {
+		t.Errorf("Take after a full period = %s, want 0", wait)
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	ctx := context.Background()
+	m, c := newTestMemory()
+
+	m.Take(ctx, "short", Limit{Burst: 1, Period: time.Second})
+	m.Take(ctx, "long", Limit{Burst: 1, Period: time.Hour})
+
+	c.Advance(sweepInterval)
+	m.Take(ctx, "other", Limit{Burst: 1, Period: time.Second})
+
+	if _, ok := m.buckets["short"]; ok {
+		t.Error("full bucket was not swept")
+	}
+	if _, ok := m.buckets["long"]; !ok {
+		t.Error("bucket still refilling was swept")
+	}
+}
@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"game/api/internal/errs"
+)
+
+const keyPrefix = "ratelimit:"
+
+// Limit é um balde de Burst fichas, reposto por completo a cada Period: até
+// Burst requisições seguidas e, em média, Burst por Period. O valor zero não
+// limita.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// fichas repostas por segundo
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// Parse lê um limite no formato <burst>/<período>, como "10/1m"; "off" ou
+// "0" desliga o limite.
+func Parse(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+	burstPart, periodPart, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <burst>/<period>", s)
+	}
+	burst, err := strconv.Atoi(burstPart)
+	if err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit burst %q", burstPart)
+	}
+	period, err := time.ParseDuration(periodPart)
+	if err != nil || period < time.Millisecond {
+		return Limit{}, fmt.Errorf("invalid rate limit period %q", periodPart)
+	}
+	return Limit{Burst: burst, Period: period}, nil
+}
+
+// ParseMap lê limites por nome no formato "place_bet=10/1s,wallet=off"
+func ParseMap(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected <name>=<burst>/<period>", item)
+		}
+		limit, err := Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits, nil
+}
+
+// Store guarda os baldes. Take consome uma ficha do balde da chave e retorna
+// zero, ou quanto falta para haver uma ficha se o balde estiver vazio.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (time.Duration, error)
+}
+
+// Allow consome uma ficha do balde name/key e retorna *errs.RetryError com
+// errs.ErrRateLimited quando o limite foi atingido
+func Allow(ctx context.Context, store Store, name, key string, limit Limit) error {
+	if !limit.Enabled() {
+		return nil
+	}
+	wait, err := store.Take(ctx, keyPrefix+name+":"+key, limit)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &errs.RetryError{Err: errs.ErrRateLimited, RetryAfter: wait}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"game/api/internal/errs"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{"10/1m", Limit{Burst: 10, Period: time.Minute}, false},
+		{" 5/1s ", Limit{Burst: 5, Period: time.Second}, false},
+		{"1/500ms", Limit{Burst: 1, Period: 500 * time.Millisecond}, false},
+		{"off", Limit{}, false},
+		{"0", Limit{}, false},
+		{"10", Limit{}, true},
+		{"0/1m", Limit{}, true},
+		{"-1/1m", Limit{}, true},
+		{"x/1m", Limit{}, true},
+		{"10/", Limit{}, true},
+		{"10/1", Limit{}, true},
+		{"10/1us", Limit{}, true},
+		{"10/-1m", Limit{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]Limit
+		wantErr bool
+	}{
+		{"empty", "", map[string]Limit{}, false},
+		{"single", "place_bet=10/1s", map[string]Limit{"place_bet": {Burst: 10, Period: time.Second}}, false},
+		{
+			"several with spaces and off", " place_bet = 10/1s , wallet=off,",
+			map[string]Limit{"place_bet": {Burst: 10, Period: time.Second}, "wallet": {}},
+			false,
+		},
+		{"missing value", "place_bet", nil, true},
+		{"invalid limit", "place_bet=10", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMap(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMap(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMap(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitString(t *testing.T) {
+	for _, s := range []string{"10/1m0s", "5/1s", "off"} {
+		limit, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if limit.String() != s {
+			t.Errorf("Parse(%q).String() = %q", s, limit.String())
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemory()
+	limit := Limit{Burst: 2, Period: time.Hour}
+
+	for i := 0; i < limit.Burst; i++ {
+		if err := Allow(ctx, store, "login", "1.2.3.4", limit); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+
+	err := Allow(ctx, store, "login", "1.2.3.4", limit)
+	var retryErr *errs.RetryError
+	if !errors.As(err, &retryErr) || !errors.Is(err, errs.ErrRateLimited) {
+		t.Fatalf("Allow after burst = %v, want a RetryError with ErrRateLimited", err)
+	}
+	if retryErr.RetryAfter <= 0 || retryErr.RetryAfter > limit.Period/2 {
+		t.Errorf("RetryAfter = %s, want up to one token (%s)", retryErr.RetryAfter, limit.Period/2)
+	}
+
+	// outra chave e outro nome têm baldes próprios
+	if err := Allow(ctx, store, "login", "5.6.7.8", limit); err != nil {
+		t.Errorf("other key: %v", err)
+	}
+	if err := Allow(ctx, store, "register", "1.2.3.4", limit); err != nil {
+		t.Errorf("other name: %v", err)
+	}
+}
+
+func TestAllowDisabled(t *testing.T) {
+	store := NewMemory()
+	for i := 0; i < 100; i++ {
+		if err := Allow(context.Background(), store, "login", "1.2.3.4", Limit{}); err != nil {
+			t.Fatalf("disabled limit refused request %d: %v", i+1, err)
+		}
+	}
+	if len(store.buckets) != 0 {
+		t.Errorf("disabled limit created %d buckets", len(store.buckets))
+	}
+}
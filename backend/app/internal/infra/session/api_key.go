@@ -0,0 +1,129 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/errs"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	ContextKeyAPIKey ContextKey = "api_key"
+	// a chave só é aceita no header: na query string ela ficaria nos logs de
+	// acesso de proxies
+	apiKeyHeader string = "X-API-Key"
+)
+
+// APIKeyPrincipal identifica um cliente autenticado por chave de API em vez
+// de sessão.
+type APIKeyPrincipal struct {
+	KeyID    string
+	ClientID string
+	Scopes   []string
+	// requisições por minuto
+	RateLimit int
+}
+
+func (p APIKeyPrincipal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyAuthenticator valida chaves de API e aplica o limite de requisições
+// de cada chave. ConsumeAPIKey retorna *errs.RetryError quando o limite foi
+// atingido.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (APIKeyPrincipal, error)
+	ConsumeAPIKey(ctx context.Context, principal APIKeyPrincipal) error
+}
+
+func (m *Manager) UseAPIKeys(auth APIKeyAuthenticator) {
+	m.apiKeys = auth
+}
+
+// ValidateJWTOrAPIKey aceita tanto o JWT de sessão quanto uma chave de API
+// com o escopo informado (vazio aceita qualquer escopo). Rotas que usam
+// ValidateJWT continuam aceitando apenas sessões.
+func (m *Manager) ValidateJWTOrAPIKey(scope string, next http.HandlerFunc) http.HandlerFunc {
+	withSession := m.ValidateJWT(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" || m.apiKeys == nil {
+			withSession(w, r)
+			return
+		}
+
+		principal, err := m.apiKeys.AuthenticateAPIKey(r.Context(), key)
+		if err != nil {
+			logger.Warnf("API key rejected: %v", err)
+			// a chave é válida, mas fica bloqueada enquanto a conta estiver
+			// suspensa
+			if errors.Is(err, errs.ErrAccountSuspended) {
+				http.Error(w, "Account suspended", http.StatusForbidden)
+				return
+			}
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if scope != "" && !principal.HasScope(scope) {
+			logger.WithFields(logrus.Fields{
+				"key_id": principal.KeyID,
+				"scope":  scope,
+			}).Warn("API key missing scope")
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ContextKeyClientID, principal.ClientID)
+		ctx = context.WithValue(ctx, ContextKeyAPIKey, principal)
+		if err := m.ConsumeAPIKey(ctx); err != nil {
+			WriteRateLimitError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// ConsumeAPIKey conta uma requisição para a chave presente no contexto. Não
+// faz nada para clientes autenticados por sessão.
+func (m *Manager) ConsumeAPIKey(ctx context.Context) error {
+	principal, ok := APIKeyFromContext(ctx)
+	if !ok || m.apiKeys == nil {
+		return nil
+	}
+	return m.apiKeys.ConsumeAPIKey(ctx, principal)
+}
+
+func APIKeyFromContext(ctx context.Context) (APIKeyPrincipal, bool) {
+	principal, ok := ctx.Value(ContextKeyAPIKey).(APIKeyPrincipal)
+	return principal, ok
+}
+
+// HasScope indica se o cliente do contexto pode executar uma operação do
+// escopo. Sessões têm acesso a tudo; chaves de API apenas aos seus escopos.
+func HasScope(ctx context.Context, scope string) bool {
+	principal, ok := APIKeyFromContext(ctx)
+	if !ok {
+		return true
+	}
+	return principal.HasScope(scope)
+}
+
+func WriteRateLimitError(w http.ResponseWriter, err error) {
+	var retryErr *errs.RetryError
+	if errors.As(err, &retryErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(retryErr.Seconds()))
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
@@ -0,0 +1,136 @@
+package session
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type BindingPolicy string
+
+const (
+	// BindingStrict exige o mesmo IP e User-Agent do login
+	BindingStrict BindingPolicy = "strict"
+	// BindingSubnet aceita troca de IP dentro da mesma sub-rede (/24 no IPv4,
+	// /64 no IPv6) e exige o mesmo User-Agent
+	BindingSubnet BindingPolicy = "subnet"
+	// BindingNone não vincula a sessão à origem
+	BindingNone BindingPolicy = "none"
+
+	subnetBitsV4 = 24
+	subnetBitsV6 = 64
+)
+
+func ParseBindingPolicy(s string) (BindingPolicy, error) {
+	switch p := BindingPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case BindingStrict, BindingSubnet, BindingNone:
+		return p, nil
+	case "":
+		return BindingStrict, nil
+	}
+	return "", fmt.Errorf("unknown session binding policy %q", s)
+}
+
+type BindingConfig struct {
+	Policy BindingPolicy
+	// proxies cujos cabeçalhos X-Forwarded-For são confiáveis
+	TrustedProxies []*net.IPNet
+}
+
+// ParseTrustedProxies aceita uma lista separada por vírgulas de IPs ou CIDRs
+func ParseTrustedProxies(s string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			ip := net.ParseIP(part)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", part)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %v", part, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func (c BindingConfig) trusted(ip net.IP) bool {
+	for _, n := range c.TrustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP determina o IP de origem da requisição. X-Forwarded-For só é
+// considerado quando a conexão vem de um proxy confiável; nesse caso a lista
+// é percorrida da direita para a esquerda e o primeiro endereço que não é de
+// um proxy confiável é o do cliente.
+func (c BindingConfig) ClientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	ip := net.ParseIP(remote)
+	if ip == nil || !c.trusted(ip) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// cabeçalho malformado: não dá para confiar no restante
+			break
+		}
+		if !c.trusted(hop) {
+			return hop.String()
+		}
+		remote = hop.String()
+	}
+	return remote
+}
+
+// Matches indica se a requisição atual é compatível com a origem registrada
+// no login, segundo a política configurada.
+func (c BindingConfig) Matches(sess *Session, ip, userAgent string) bool {
+	switch c.Policy {
+	case BindingNone:
+		return true
+	case BindingSubnet:
+		return sess.UserAgent == userAgent && sameSubnet(sess.IP, ip)
+	default:
+		return sess.UserAgent == userAgent && sess.IP == ip
+	}
+}
+
+func sameSubnet(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	if v4A, v4B := ipA.To4(), ipB.To4(); v4A != nil || v4B != nil {
+		if v4A == nil || v4B == nil {
+			return false
+		}
+		mask := net.CIDRMask(subnetBitsV4, 32)
+		return v4A.Mask(mask).Equal(v4B.Mask(mask))
+	}
+	mask := net.CIDRMask(subnetBitsV6, 128)
+	return ipA.Mask(mask).Equal(ipB.Mask(mask))
+}
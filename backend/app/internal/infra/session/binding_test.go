@@ -0,0 +1,139 @@
+package session
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func mustTrustedProxies(t *testing.T, s string) BindingConfig {
+	t.Helper()
+	proxies, err := ParseTrustedProxies(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return BindingConfig{TrustedProxies: proxies}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"10.0.0.1", 1, false},
+		{"10.0.0.0/8, 192.168.0.0/16,", 2, false},
+		{"::1, fd00::/8", 2, false},
+		{"proxy.local", 0, true},
+		{"10.0.0.0/33", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTrustedProxies(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTrustedProxies(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ParseTrustedProxies(%q) = %d networks, want %d", tt.in, len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	config := mustTrustedProxies(t, "10.0.0.0/8, 2001:db8::1")
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"direct connection", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted peer header is ignored", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy without header", "10.0.0.2:5000", nil, "10.0.0.2"},
+		{"trusted proxy", "10.0.0.2:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed left entries are skipped", "10.0.0.2:5000", []string{"1.1.1.1, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:5000", []string{"198.51.100.1, 10.0.0.3, 10.0.0.4"}, "198.51.100.1"},
+		{"repeated headers are joined", "10.0.0.2:5000", []string{"198.51.100.1", "10.0.0.3"}, "198.51.100.1"},
+		{"only trusted hops", "10.0.0.2:5000", []string{"10.0.0.3, 10.0.0.4"}, "10.0.0.3"},
+		{"malformed hop stops the walk", "10.0.0.2:5000", []string{"198.51.100.1, garbage, 10.0.0.3"}, "10.0.0.3"},
+		{"malformed last hop", "10.0.0.2:5000", []string{"198.51.100.1, garbage"}, "10.0.0.2"},
+		{"IPv6 trusted proxy", "[2001:db8::1]:5000", []string{"2001:db8::beef"}, "2001:db8::beef"},
+		{"remote without port", "203.0.113.7", nil, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := config.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.2:5000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+	if got := (BindingConfig{}).ClientIP(r); got != "10.0.0.2" {
+		t.Errorf("ClientIP = %q, want the peer address", got)
+	}
+}
+
+func TestSameSubnet(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"192.168.1.10", "192.168.1.10", true},
+		{"192.168.1.10", "192.168.1.200", true},
+		{"192.168.1.10", "192.168.2.10", false},
+		{"192.168.1.10", "::ffff:192.168.1.20", true},
+		{"2001:db8:1:2::1", "2001:db8:1:2:ffff::1", true},
+		{"2001:db8:1:2::1", "2001:db8:1:3::1", false},
+		{"192.168.1.10", "2001:db8::1", false},
+		{"not an ip", "not an ip", true},
+		{"not an ip", "192.168.1.10", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := sameSubnet(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameSubnet(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := sameSubnet(tt.b, tt.a); got != tt.want {
+				t.Errorf("sameSubnet(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	sess := &Session{IP: "192.168.1.10", UserAgent: "browser"}
+	tests := []struct {
+		policy    BindingPolicy
+		ip, agent string
+		want      bool
+	}{
+		{BindingStrict, "192.168.1.10", "browser", true},
+		{BindingStrict, "192.168.1.11", "browser", false},
+		{BindingStrict, "192.168.1.10", "other", false},
+		{BindingSubnet, "192.168.1.11", "browser", true},
+		{BindingSubnet, "192.168.2.10", "browser", false},
+		{BindingSubnet, "192.168.1.11", "other", false},
+		{BindingNone, "203.0.113.7", "other", true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy)+" "+tt.ip+" "+tt.agent, func(t *testing.T) {
+			config := BindingConfig{Policy: tt.policy}
+			if got := config.Matches(sess, tt.ip, tt.agent); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
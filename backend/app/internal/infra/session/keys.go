@@ -0,0 +1,472 @@
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"game/api/internal/infra/lock"
+	"game/api/internal/infra/logger"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	keysHashKey    = "jwt:keys"
+	keysCurrentKey = "jwt:keys:current"
+	keysLockKey    = "lock:jwt:keys"
+	hmacKeyID      = "hs256"
+	rsaKeyBits     = 2048
+)
+
+var (
+	ErrUnknownAlgorithm  = errors.New("unknown signing algorithm")
+	ErrUnknownKeyID      = errors.New("unknown key id")
+	ErrAlgorithmMismatch = errors.New("signing algorithm does not match key")
+	ErrNoSigningKey      = errors.New("no active signing key")
+	ErrKeyEncrypted      = errors.New("signing key is encrypted and no encryption secret is set")
+)
+
+type KeyConfig struct {
+	Algorithm string
+	Secret    string
+	// cifra as chaves privadas guardadas no Redis (RS256 e EdDSA); sem ele,
+	// quem lê o Redis pode assinar tokens
+	EncryptionSecret string
+	RotationInterval time.Duration
+	Grace            time.Duration
+}
+
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	createdAt time.Time
+	retiredAt *time.Time
+}
+
+type storedKey struct {
+	Kid     string `json:"kid"`
+	Alg     string `json:"alg"`
+	Private []byte `json:"private"`
+	// presente quando Private está cifrada com AES-GCM
+	Nonce     []byte     `json:"nonce,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet guarda as chaves de assinatura dos tokens. Chaves assimétricas são
+// compartilhadas entre instâncias pelo Redis e rotacionadas periodicamente;
+// chaves aposentadas continuam válidas para verificação durante o grace.
+type KeySet struct {
+	client *redis.Client
+	lock   *lock.Lock
+	config KeyConfig
+	aead   cipher.AEAD
+
+	mu      sync.RWMutex
+	keys    map[string]*signingKey
+	current string
+}
+
+func NewKeySet(ctx context.Context, client *redis.Client, config KeyConfig) (*KeySet, error) {
+	k := &KeySet{
+		client: client,
+		lock:   lock.NewLock(client),
+		config: config,
+		keys:   make(map[string]*signingKey),
+	}
+
+	switch config.Algorithm {
+	case AlgorithmHS256:
+		if config.Secret == "" {
+			return nil, fmt.Errorf("secret is required for %s", AlgorithmHS256)
+		}
+		k.keys[hmacKeyID] = &signingKey{
+			kid:       hmacKeyID,
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(config.Secret),
+			verifyKey: []byte(config.Secret),
+			createdAt: time.Now(),
+		}
+		k.current = hmacKeyID
+		return k, nil
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	if config.EncryptionSecret != "" {
+		var err error
+		if k.aead, err = newAEAD(config.EncryptionSecret); err != nil {
+			return nil, err
+		}
+	} else {
+		logger.Warn("Signing keys are stored unencrypted in Redis; set an encryption secret")
+	}
+
+	if err := k.load(ctx); err != nil {
+		return nil, err
+	}
+	if k.needsRotation() {
+		if err := k.Rotate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+func (k *KeySet) Algorithm() string {
+	return k.config.Algorithm
+}
+
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	key, ok := k.keys[k.current]
+	k.mu.RUnlock()
+	if !ok {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.signKey)
+}
+
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && k.config.Algorithm == AlgorithmHS256 {
+		// tokens emitidos antes da introdução do kid
+		kid = hmacKeyID
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrAlgorithmMismatch
+	}
+	if key.retiredAt != nil && time.Since(*key.retiredAt) > k.config.Grace {
+		return nil, ErrUnknownKeyID
+	}
+	return key.verifyKey, nil
+}
+
+func (k *KeySet) ValidMethods() []string {
+	return []string{k.config.Algorithm}
+}
+
+func (k *KeySet) JWKS() JWKSet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		if key.retiredAt != nil && time.Since(*key.retiredAt) > k.config.Grace {
+			continue
+		}
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.kid,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.kid,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+// Run recarrega periodicamente as chaves do Redis e rotaciona a chave
+// corrente quando ela ultrapassa o intervalo configurado.
+func (k *KeySet) Run(ctx context.Context) {
+	if k.config.Algorithm == AlgorithmHS256 {
+		return
+	}
+
+	interval := k.config.RotationInterval / 10
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := k.load(ctx); err != nil {
+				logger.Errorf("Failed to reload signing keys: %v", err)
+				continue
+			}
+			if k.needsRotation() {
+				if err := k.Rotate(ctx); err != nil {
+					logger.Errorf("Failed to rotate signing key: %v", err)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (k *KeySet) Rotate(ctx context.Context) error {
+	if k.config.Algorithm == AlgorithmHS256 {
+		return nil
+	}
+
+	err := k.lock.WithLock(ctx, keysLockKey, 30*time.Second, 10, 200*time.Millisecond, func() error {
+		if err := k.load(ctx); err != nil {
+			return err
+		}
+		// outra instância pode ter rotacionado enquanto esperávamos o lock
+		if !k.needsRotation() {
+			return nil
+		}
+
+		now := time.Now()
+		next, err := k.generate(now)
+		if err != nil {
+			return err
+		}
+
+		// as chaves em uso são lidas por Keyfunc e JWKS sem o lock de
+		// escrita, então a aposentadoria é feita em cópias; load as substitui
+		k.mu.RLock()
+		retired := make([]signingKey, 0, len(k.keys))
+		for _, key := range k.keys {
+			retired = append(retired, *key)
+		}
+		k.mu.RUnlock()
+
+		pipe := k.client.TxPipeline()
+		for i := range retired {
+			key := &retired[i]
+			if key.retiredAt == nil {
+				key.retiredAt = &now
+			}
+			if now.Sub(*key.retiredAt) > k.config.Grace {
+				pipe.HDel(ctx, keysHashKey, key.kid)
+				continue
+			}
+			data, err := k.encode(key)
+			if err != nil {
+				return err
+			}
+			pipe.HSet(ctx, keysHashKey, key.kid, data)
+		}
+
+		data, err := k.encode(next)
+		if err != nil {
+			return err
+		}
+		pipe.HSet(ctx, keysHashKey, next.kid, data)
+		pipe.Set(ctx, keysCurrentKey, next.kid, 0)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+
+		logger.WithFields(logrus.Fields{
+			"kid": next.kid,
+			"alg": k.config.Algorithm,
+		}).Info("Signing key rotated")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return k.load(ctx)
+}
+
+func (k *KeySet) needsRotation() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[k.current]
+	if !ok || key.method.Alg() != k.config.Algorithm {
+		return true
+	}
+	return k.config.RotationInterval > 0 && time.Since(key.createdAt) >= k.config.RotationInterval
+}
+
+func (k *KeySet) load(ctx context.Context) error {
+	current, err := k.client.Get(ctx, keysCurrentKey).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	stored, err := k.client.HGetAll(ctx, keysHashKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*signingKey, len(stored))
+	for kid, data := range stored {
+		key, err := k.decode([]byte(data))
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"kid": kid,
+			}).Errorf("Failed to decode signing key: %v", err)
+			continue
+		}
+		keys[kid] = key
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.current = current
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *KeySet) generate(now time.Time) (*signingKey, error) {
+	key := &signingKey{
+		kid:       uuid.New().String(),
+		createdAt: now,
+	}
+
+	switch k.config.Algorithm {
+	case AlgorithmRS256:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		key.method = jwt.SigningMethodRS256
+		key.signKey = private
+		key.verifyKey = &private.PublicKey
+	case AlgorithmEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key.method = jwt.SigningMethodEdDSA
+		key.signKey = private
+		key.verifyKey = public
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+	return key, nil
+}
+
+func (k *KeySet) encode(key *signingKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key.signKey)
+	if err != nil {
+		return nil, err
+	}
+	s := storedKey{
+		Kid:       key.kid,
+		Alg:       key.method.Alg(),
+		Private:   der,
+		CreatedAt: key.createdAt,
+		RetiredAt: key.retiredAt,
+	}
+	if k.aead != nil {
+		s.Nonce = make([]byte, k.aead.NonceSize())
+		if _, err := rand.Read(s.Nonce); err != nil {
+			return nil, err
+		}
+		// o kid como dado associado impede trocar chaves cifradas de lugar
+		s.Private = k.aead.Seal(nil, s.Nonce, der, []byte(key.kid))
+	}
+	return json.Marshal(s)
+}
+
+func (k *KeySet) decode(data []byte) (*signingKey, error) {
+	var s storedKey
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	// chaves gravadas antes de o segredo ser configurado continuam em claro
+	// até saírem do grace
+	der := s.Private
+	if s.Nonce != nil {
+		if k.aead == nil {
+			return nil, ErrKeyEncrypted
+		}
+		var err error
+		if der, err = k.aead.Open(nil, s.Nonce, s.Private, []byte(s.Kid)); err != nil {
+			return nil, err
+		}
+	}
+	private, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{
+		kid:       s.Kid,
+		createdAt: s.CreatedAt,
+		retiredAt: s.RetiredAt,
+	}
+	switch p := private.(type) {
+	case *rsa.PrivateKey:
+		key.method = jwt.SigningMethodRS256
+		key.signKey = p
+		key.verifyKey = &p.PublicKey
+	case ed25519.PrivateKey:
+		key.method = jwt.SigningMethodEdDSA
+		key.signKey = p
+		key.verifyKey = p.Public()
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+	return key, nil
+}
+
+// newAEAD deriva do segredo uma chave AES-256
+func newAEAD(secret string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
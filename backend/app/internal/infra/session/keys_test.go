@@ -0,0 +1,310 @@
+package session
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newTestKeySet monta o keyset sem Redis: a chave corrente e as chaves
+// informadas, como se tivessem sido carregadas por load
+func newTestKeySet(t *testing.T, alg string, others ...*signingKey) (*KeySet, *signingKey) {
+	t.Helper()
+	k := &KeySet{
+		config: KeyConfig{Algorithm: alg, Grace: time.Hour},
+		keys:   make(map[string]*signingKey),
+	}
+	current, err := k.generate(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.keys[current.kid] = current
+	k.current = current.kid
+	for _, key := range others {
+		k.keys[key.kid] = key
+	}
+	return k, current
+}
+
+func generateKey(t *testing.T, alg string, retiredAgo time.Duration) *signingKey {
+	t.Helper()
+	key, err := (&KeySet{config: KeyConfig{Algorithm: alg}}).generate(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retiredAgo > 0 {
+		retiredAt := time.Now().Add(-retiredAgo)
+		key.retiredAt = &retiredAt
+	}
+	return key
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"client_id": "client",
+		"iss":       tokenIssuer,
+		"exp":       time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func sign(t *testing.T, key *signingKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(key.method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key.signKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestParseTokenAsymmetric(t *testing.T) {
+	retired := generateKey(t, AlgorithmRS256, time.Minute)
+	expired := generateKey(t, AlgorithmRS256, 2*time.Hour)
+	edKey := generateKey(t, AlgorithmEdDSA, time.Minute)
+	unknown := generateKey(t, AlgorithmRS256, 0)
+	keys, current := newTestKeySet(t, AlgorithmRS256, retired, expired, edKey)
+	m := NewManager(nil, time.Hour, keys, BindingConfig{})
+
+	publicDER, err := x509.MarshalPKIXPublicKey(current.verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ataque clássico: HS256 usando a chave pública (conhecida) como segredo
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	confused.Header["kid"] = current.kid
+	confusedToken, err := confused.SignedString(publicDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+	none.Header["kid"] = current.kid
+	noneToken, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiredClaims := validClaims()
+	expiredClaims["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "other"
+	noExpiry := validClaims()
+	delete(noExpiry, "exp")
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"current key", sign(t, current, current.kid, validClaims()), nil},
+		{"retired key within grace", sign(t, retired, retired.kid, validClaims()), nil},
+		{"retired key after grace", sign(t, expired, expired.kid, validClaims()), ErrUnknownKeyID},
+		{"unknown kid", sign(t, unknown, unknown.kid, validClaims()), ErrUnknownKeyID},
+		{"missing kid", sign(t, current, "", validClaims()), ErrUnknownKeyID},
+		{"kid of another key", sign(t, unknown, current.kid, validClaims()), jwt.ErrTokenSignatureInvalid},
+		{"HS256 with the public key as secret", confusedToken, jwt.ErrTokenSignatureInvalid},
+		{"alg none", noneToken, jwt.ErrTokenSignatureInvalid},
+		{"EdDSA key of the set", sign(t, edKey, edKey.kid, validClaims()), jwt.ErrTokenSignatureInvalid},
+		{"expired", sign(t, current, current.kid, expiredClaims), jwt.ErrTokenExpired},
+		{"wrong issuer", sign(t, current, current.kid, wrongIssuer), jwt.ErrTokenInvalidIssuer},
+		{"no expiry", sign(t, current, current.kid, noExpiry), jwt.ErrTokenRequiredClaimMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.parseToken(tt.token)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("parseToken: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseToken error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyfuncAlgorithmMismatch(t *testing.T) {
+	edKey := generateKey(t, AlgorithmEdDSA, 0)
+	keys, _ := newTestKeySet(t, AlgorithmRS256, edKey)
+
+	// ValidMethods já recusa o EdDSA; Keyfunc também confere o algoritmo da
+	// chave, para o caso de o keyset aceitar mais de um
+	token := &jwt.Token{
+		Method: jwt.SigningMethodRS256,
+		Header: map[string]interface{}{"kid": edKey.kid},
+	}
+	if _, err := keys.Keyfunc(token); !errors.Is(err, ErrAlgorithmMismatch) {
+		t.Errorf("Keyfunc error = %v, want %v", err, ErrAlgorithmMismatch)
+	}
+}
+
+func TestParseTokenHS256(t *testing.T) {
+	keys, err := NewKeySet(nil, nil, KeyConfig{Algorithm: AlgorithmHS256, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(nil, time.Hour, keys, BindingConfig{})
+	key := keys.keys[hmacKeyID]
+	other := &signingKey{method: jwt.SigningMethodHS256, signKey: []byte("other")}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"with kid", sign(t, key, hmacKeyID, validClaims()), nil},
+		{"without kid, issued before kids", sign(t, key, "", validClaims()), nil},
+		{"unknown kid", sign(t, key, "other", validClaims()), ErrUnknownKeyID},
+		{"other secret", sign(t, other, hmacKeyID, validClaims()), jwt.ErrTokenSignatureInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.parseToken(tt.token)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("parseToken: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseToken error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// validate recusa o token antes de consultar a sessão no Redis
+func TestValidateRejectsBeforeSessionLookup(t *testing.T) {
+	unknown := generateKey(t, AlgorithmRS256, 0)
+	keys, current := newTestKeySet(t, AlgorithmRS256)
+	m := NewManager(nil, time.Hour, keys, BindingConfig{})
+
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	confused.Header["kid"] = current.kid
+	publicDER, _ := x509.MarshalPKIXPublicKey(current.verifyKey)
+	confusedToken, _ := confused.SignedString(publicDER)
+
+	for name, token := range map[string]string{
+		"unknown kid":   sign(t, unknown, unknown.kid, validClaims()),
+		"alg confusion": confusedToken,
+		"no token":      "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			handler := m.ValidateJWT(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+			if called || w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, handler called = %v, want 401 without calling it", w.Code, called)
+			}
+		})
+	}
+}
+
+func TestKeyEncryptionAtRest(t *testing.T) {
+	aead, err := newAEAD("encryption secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAEAD, err := newAEAD("other secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := generateKey(t, AlgorithmEdDSA, 0)
+	encrypted := &KeySet{aead: aead}
+	plain := &KeySet{}
+
+	stored, err := encrypted.encode(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := plain.encode(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key.signKey)
+	if s := unmarshalStored(t, stored); bytes.Equal(s.Private, der) || s.Nonce == nil {
+		t.Fatal("private key stored in plaintext")
+	}
+
+	// o kid é o dado associado: a chave cifrada não pode ser movida para
+	// outro kid
+	swapped := swapKid(t, stored, "other-kid")
+
+	tests := []struct {
+		name    string
+		keys    *KeySet
+		data    []byte
+		wantErr bool
+		errIs   error
+	}{
+		{"encrypted with the same secret", encrypted, stored, false, nil},
+		{"legacy plaintext key", encrypted, legacy, false, nil},
+		{"plaintext without secret", plain, legacy, false, nil},
+		{"encrypted without secret", plain, stored, true, ErrKeyEncrypted},
+		{"encrypted with another secret", &KeySet{aead: otherAEAD}, stored, true, nil},
+		{"kid swapped", encrypted, swapped, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keys.decode(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("decode accepted the key")
+				}
+				if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+					t.Errorf("decode error = %v, want %v", err, tt.errIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.kid != key.kid || got.method != key.method {
+				t.Errorf("decode = %s/%s, want %s/%s", got.kid, got.method.Alg(), key.kid, key.method.Alg())
+			}
+			token := sign(t, got, got.kid, validClaims())
+			if _, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return key.verifyKey, nil }); err != nil {
+				t.Errorf("decoded key does not sign for the original key: %v", err)
+			}
+		})
+	}
+}
+
+func unmarshalStored(t *testing.T, data []byte) storedKey {
+	t.Helper()
+	var s storedKey
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func swapKid(t *testing.T, data []byte, kid string) []byte {
+	t.Helper()
+	s := unmarshalStored(t, data)
+	s.Kid = kid
+	swapped, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return swapped
+}
@@ -22,32 +22,128 @@ const (
 	ContextKeyUserAgent ContextKey = "user_agent"
 	ContextKeyClientID  ContextKey = "client_id"
 	ContextKeySessionID ContextKey = "session_id"
+	ContextKeyToken     ContextKey = "token"
+	ContextKeyRole      ContextKey = "role"
 	sessionKeyPrefix    string     = "session:"
+	clientSessionsKey   string     = "client_sessions:"
+	tokenIssuer         string     = "game-api"
+	pendingSessionTTL              = 5 * time.Minute
 )
 
 type Session struct {
-	ClientID     string    `json:"client_id"`
-	IP           string    `json:"ip"`
-	UserAgent    string    `json:"user_agent"`
-	CreatedAt    time.Time `json:"created_at"`
-	LastActivity time.Time `json:"last_activity"`
+	ClientID      string     `json:"client_id"`
+	Role          string     `json:"role"`
+	IP            string     `json:"ip"`
+	UserAgent     string     `json:"user_agent"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastActivity  time.Time  `json:"last_activity"`
+	MFAPending    bool       `json:"mfa_pending,omitempty"`
+	MFAVerifiedAt *time.Time `json:"mfa_verified_at,omitempty"`
 }
 
 type Manager struct {
-	client    *redis.Client
-	ttl       time.Duration
-	jwtSecret []byte
+	client  *redis.Client
+	ttl     time.Duration
+	keys    *KeySet
+	binding BindingConfig
+	apiKeys APIKeyAuthenticator
+	revoked RevokeHandler
+	mfa     MFAStatus
 }
 
-func NewManager(client *redis.Client, ttl time.Duration, jwtSecret string) *Manager {
+// RevokeHandler é chamado depois que sessões são removidas (logout,
+// revogação), para que conexões abertas com esses tokens sejam encerradas.
+type RevokeHandler func(ctx context.Context, clientID string, tokens []string)
+
+// MFAStatus informa se o cliente tem 2FA habilitado
+type MFAStatus func(ctx context.Context, clientID string) (bool, error)
+
+// validateOptions define quais sessões uma rota aceita
+type validateOptions struct {
+	// apenas sessões parciais do login com 2FA
+	pending bool
+	// aceita sessões cuja origem não confere com a política de vínculo, para
+	// que o cliente possa se reautenticar
+	unbound bool
+}
+
+func NewManager(client *redis.Client, ttl time.Duration, keys *KeySet, binding BindingConfig) *Manager {
+	if binding.Policy == "" {
+		binding.Policy = BindingStrict
+	}
 	return &Manager{
-		client:    client,
-		ttl:       ttl,
-		jwtSecret: []byte(jwtSecret),
+		client:  client,
+		ttl:     ttl,
+		keys:    keys,
+		binding: binding,
 	}
 }
 
+func (m *Manager) OnRevoke(h RevokeHandler) {
+	m.revoked = h
+}
+
+func (m *Manager) UseMFAStatus(s MFAStatus) {
+	m.mfa = s
+}
+
 func (m *Manager) ValidateJWT(next http.HandlerFunc) http.HandlerFunc {
+	return m.validate(next, validateOptions{})
+}
+
+// Middleware adapta ValidateJWT para grupos de rotas do chi
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return m.ValidateJWT(next.ServeHTTP)
+}
+
+// ValidatePartialJWT aceita apenas tokens parciais emitidos no login de
+// clientes com 2FA, usados para concluir o segundo passo.
+func (m *Manager) ValidatePartialJWT(next http.HandlerFunc) http.HandlerFunc {
+	return m.validate(next, validateOptions{pending: true})
+}
+
+// ValidateUnboundJWT aceita sessões vindas de outra origem (IP/User-Agent),
+// usado apenas pela rota de reautenticação.
+func (m *Manager) ValidateUnboundJWT(next http.HandlerFunc) http.HandlerFunc {
+	return m.validate(next, validateOptions{unbound: true})
+}
+
+func (m *Manager) ClientIP(r *http.Request) string {
+	return m.binding.ClientIP(r)
+}
+
+// RequireFreshMFA exige que o código 2FA tenha sido confirmado na sessão
+// atual há no máximo maxAge (no login ou em /2fa/confirm). Clientes sem 2FA
+// passam direto. Deve ser usado dentro de ValidateJWT.
+func (m *Manager) RequireFreshMFA(maxAge time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, _ := r.Context().Value(ContextKeyToken).(string)
+		sess, err := m.Get(r.Context(), token)
+		if err != nil || sess == nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if sess.MFAVerifiedAt != nil && time.Since(*sess.MFAVerifiedAt) <= maxAge {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if m.mfa != nil {
+			enabled, err := m.mfa(r.Context(), sess.ClientID)
+			if err != nil {
+				logger.Errorf("Failed to check two-factor status: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if !enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.Error(w, "Two-factor verification required", http.StatusForbidden)
+	}
+}
+
+func (m *Manager) validate(next http.HandlerFunc, opts validateOptions) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger.Debug("Validating JWT token")
 
@@ -58,23 +154,13 @@ func (m *Manager) ValidateJWT(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		claims := jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			return m.jwtSecret, nil
-		})
-
+		claims, err := m.parseToken(tokenStr)
 		if err != nil {
 			logger.Errorf("Failed to parse token: %v", err)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		if !token.Valid {
-			logger.Warn("Invalid token")
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
 		clientID, ok := claims["client_id"].(string)
 		if !ok {
 			logger.Error("client_id not found in token claims")
@@ -95,27 +181,31 @@ func (m *Manager) ValidateJWT(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		currentIP := r.RemoteAddr
-		if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
-			currentIP = forwardedFor
-		}
-		currentUserAgent := r.UserAgent()
-
-		if sess.IP != currentIP {
+		if sess.MFAPending != opts.pending {
 			logger.WithFields(logrus.Fields{
-				"session_ip": sess.IP,
-				"current_ip": currentIP,
-			}).Warn("IP mismatch")
+				"client_id":   clientID,
+				"mfa_pending": sess.MFAPending,
+			}).Warn("Session MFA state not accepted by this route")
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		if sess.UserAgent != currentUserAgent {
+		currentIP := m.binding.ClientIP(r)
+		currentUserAgent := r.UserAgent()
+
+		if !opts.unbound && !m.binding.Matches(sess, currentIP, currentUserAgent) {
 			logger.WithFields(logrus.Fields{
+				"client_id":          clientID,
+				"policy":             m.binding.Policy,
+				"session_ip":         sess.IP,
+				"current_ip":         currentIP,
 				"session_user_agent": sess.UserAgent,
 				"current_user_agent": currentUserAgent,
-			}).Warn("User-Agent mismatch")
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			}).Warn("Session binding mismatch, re-authentication required")
+			// a sessão continua válida; o cliente confirma a senha em
+			// /session/reauth para vinculá-la à nova origem
+			w.Header().Set("WWW-Authenticate", `Bearer error="reauth_required"`)
+			http.Error(w, "Re-authentication required", http.StatusUnauthorized)
 			return
 		}
 
@@ -130,11 +220,33 @@ func (m *Manager) ValidateJWT(next http.HandlerFunc) http.HandlerFunc {
 			"client_id": clientID,
 		}).Debug("Token validated successfully")
 
+		role, _ := claims["role"].(string)
+
 		ctx := context.WithValue(r.Context(), ContextKeyClientID, clientID)
+		ctx = context.WithValue(ctx, ContextKeyToken, tokenStr)
+		ctx = context.WithValue(ctx, ContextKeyRole, role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
 
+// parseToken verifica assinatura, kid, algoritmo, emissor e expiração do
+// token; a sessão correspondente é conferida por validate
+func (m *Manager) parseToken(tokenStr string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, m.keys.Keyfunc,
+		jwt.WithValidMethods(m.keys.ValidMethods()),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
 func extractToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader != "" {
@@ -159,7 +271,17 @@ func extractToken(r *http.Request) (string, error) {
 }
 
 func (m *Manager) Create(ctx context.Context, session Session) (token string, err error) {
-	token, err = m.generateJWT(session.ClientID, session.IP, session.UserAgent)
+	return m.create(ctx, session, m.ttl)
+}
+
+// CreatePending cria a sessão parcial do primeiro passo do login com 2FA
+func (m *Manager) CreatePending(ctx context.Context, session Session) (token string, err error) {
+	session.MFAPending = true
+	return m.create(ctx, session, pendingSessionTTL)
+}
+
+func (m *Manager) create(ctx context.Context, session Session, ttl time.Duration) (token string, err error) {
+	token, err = m.generateJWT(session, ttl)
 	if err != nil {
 		logger.Errorf("Failed to generate JWT: %v", err)
 		return
@@ -178,7 +300,12 @@ func (m *Manager) Create(ctx context.Context, session Session) (token string, er
 		return
 	}
 	key := sessionKeyPrefix + token
-	err = m.client.Set(ctx, key, data, m.ttl).Err()
+	indexKey := clientSessionsKey + session.ClientID
+	pipe := m.client.TxPipeline()
+	pipe.Set(ctx, key, data, ttl)
+	pipe.SAdd(ctx, indexKey, token)
+	pipe.Expire(ctx, indexKey, m.ttl)
+	_, err = pipe.Exec(ctx)
 	if err != nil {
 		logger.Errorf("Failed to save session: %v", err)
 		return
@@ -227,14 +354,59 @@ func (m *Manager) UpdateActivity(ctx context.Context, token string) error {
 	}
 
 	session.LastActivity = time.Now()
+	return m.save(ctx, token, session)
+}
+
+// Rebind vincula a sessão à nova origem após a reautenticação
+func (m *Manager) Rebind(ctx context.Context, token, ip, userAgent string) error {
+	session, err := m.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("session not found")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": session.ClientID,
+		"from_ip":   session.IP,
+		"to_ip":     ip,
+	}).Info("Session rebound to new origin")
+
+	session.IP = ip
+	session.UserAgent = userAgent
+	return m.save(ctx, token, session)
+}
+
+func (m *Manager) MarkMFAVerified(ctx context.Context, token string) error {
+	session, err := m.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return nil
+	}
+
+	now := time.Now()
+	session.MFAVerifiedAt = &now
+	return m.save(ctx, token, session)
+}
+
+func (m *Manager) save(ctx context.Context, token string, session *Session) error {
 	data, err := json.Marshal(session)
 	if err != nil {
 		logger.Errorf("Failed to marshal session: %v", err)
 		return err
 	}
 
+	// sessões parciais mantêm o TTL curto original
+	ttl := m.ttl
+	if session.MFAPending {
+		ttl = redis.KeepTTL
+	}
+
 	key := sessionKeyPrefix + token
-	err = m.client.Set(ctx, key, data, m.ttl).Err()
+	err = m.client.Set(ctx, key, data, ttl).Err()
 	if err != nil {
 		logger.Errorf("Failed to update session: %v", err)
 		return err
@@ -248,29 +420,114 @@ func (m *Manager) Delete(ctx context.Context, token string) error {
 		"token": token,
 	}).Debug("Deleting session")
 
+	session, err := m.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
 	key := sessionKeyPrefix + token
-	err := m.client.Del(ctx, key).Err()
+	pipe := m.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if session != nil {
+		pipe.SRem(ctx, clientSessionsKey+session.ClientID, token)
+	}
+	_, err = pipe.Exec(ctx)
 	if err != nil {
 		logger.Errorf("Failed to delete session: %v", err)
 		return err
 	}
 
+	if session != nil && !session.MFAPending {
+		m.notifyRevoked(ctx, session.ClientID, []string{token})
+	}
 	return nil
 }
 
-func (m *Manager) generateJWT(clientID, ip, userAgent string) (string, error) {
+// RevokeAll remove todas as sessões do cliente, exceto a informada em except
+// (vazio revoga todas). Retorna os tokens revogados.
+func (m *Manager) RevokeAll(ctx context.Context, clientID, except string) ([]string, error) {
+	indexKey := clientSessionsKey + clientID
+	tokens, err := m.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		logger.Errorf("Failed to list client sessions: %v", err)
+		return nil, err
+	}
+
+	revoked := make([]string, 0, len(tokens))
+	pipe := m.client.TxPipeline()
+	for _, token := range tokens {
+		if token == except {
+			continue
+		}
+		pipe.Del(ctx, sessionKeyPrefix+token)
+		pipe.SRem(ctx, indexKey, token)
+		revoked = append(revoked, token)
+	}
+	if len(revoked) == 0 {
+		return revoked, nil
+	}
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		logger.Errorf("Failed to revoke client sessions: %v", err)
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"client_id": clientID,
+		"revoked":   len(revoked),
+	}).Info("Client sessions revoked")
+	m.notifyRevoked(ctx, clientID, revoked)
+	return revoked, nil
+}
+
+func (m *Manager) notifyRevoked(ctx context.Context, clientID string, tokens []string) {
+	if m.revoked != nil {
+		m.revoked(ctx, clientID, tokens)
+	}
+}
+
+// List retorna as sessões ativas do cliente. Índices de sessões já expiradas
+// são ignorados.
+func (m *Manager) List(ctx context.Context, clientID string) ([]Session, error) {
+	tokens, err := m.client.SMembers(ctx, clientSessionsKey+clientID).Result()
+	if err != nil {
+		logger.Errorf("Failed to list client sessions: %v", err)
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		session, err := m.Get(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if session != nil {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *Manager) JWKS() JWKSet {
+	return m.keys.JWKS()
+}
+
+func (m *Manager) generateJWT(session Session, ttl time.Duration) (string, error) {
 
 	claims := jwt.MapClaims{
-		"client_id":  clientID,
-		"ip":         ip,
-		"user_agent": userAgent,
-		"iss":        "game-api",
-		"exp":        time.Now().Add(24 * time.Hour).Unix(),
+		"client_id":  session.ClientID,
+		"role":       session.Role,
+		"ip":         session.IP,
+		"user_agent": session.UserAgent,
+		"iss":        tokenIssuer,
+		"exp":        time.Now().Add(ttl).Unix(),
 		"iat":        time.Now().Unix(),
 	}
+	if session.MFAPending {
+		claims["mfa"] = "pending"
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(m.jwtSecret)
+	tokenString, err := m.keys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
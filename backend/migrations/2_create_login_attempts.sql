@@ -0,0 +1,17 @@
+\c game
+
+CREATE TABLE IF NOT EXISTS "public"."login_attempts" (
+    "guid" UUID PRIMARY KEY,
+    "username" VARCHAR(60) NOT NULL,
+    "client_id" UUID,
+    "ip" VARCHAR(255) NOT NULL,
+    "user_agent" TEXT NOT NULL DEFAULT '',
+    "reason" VARCHAR(30) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_login_attempt_client FOREIGN KEY (client_id) REFERENCES clients(guid)
+        ON DELETE SET NULL
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_username ON "public"."login_attempts" (username, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON "public"."login_attempts" (ip, created_at);
@@ -0,0 +1,18 @@
+\c game
+
+ALTER TABLE "public"."clients"
+    ADD COLUMN IF NOT EXISTS "totp_secret" VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS "totp_enabled_at" TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS "public"."recovery_codes" (
+    "guid" UUID PRIMARY KEY,
+    "client_id" UUID NOT NULL,
+    "code_hash" VARCHAR(64) NOT NULL,
+    "used_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_recovery_code_client FOREIGN KEY (client_id) REFERENCES clients(guid)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_client ON "public"."recovery_codes" (client_id);
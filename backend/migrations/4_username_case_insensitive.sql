@@ -0,0 +1,3 @@
+\c game
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_username_lower ON "public"."clients" (LOWER(username));
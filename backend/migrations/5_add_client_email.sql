@@ -0,0 +1,7 @@
+\c game
+
+ALTER TABLE "public"."clients"
+    ADD COLUMN IF NOT EXISTS "email" VARCHAR(254),
+    ADD COLUMN IF NOT EXISTS "email_verified_at" TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_email_lower ON "public"."clients" (LOWER(email));
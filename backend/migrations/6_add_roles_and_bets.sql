@@ -0,0 +1,30 @@
+\c game
+
+ALTER TABLE "public"."clients"
+    ADD COLUMN IF NOT EXISTS "role" VARCHAR(20) NOT NULL DEFAULT 'player',
+    ADD COLUMN IF NOT EXISTS "suspended_at" TIMESTAMPTZ;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_client_role') THEN
+        ALTER TABLE "public"."clients"
+            ADD CONSTRAINT chk_client_role CHECK (role IN ('player', 'support', 'admin'));
+    END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS "public"."bets" (
+    "guid" UUID PRIMARY KEY,
+    "client_id" UUID NOT NULL,
+    "amount" DOUBLE PRECISION NOT NULL,
+    "choice" VARCHAR(10) NOT NULL,
+    "number" INTEGER NOT NULL,
+    "result" VARCHAR(10) NOT NULL,
+    "balance_after" DOUBLE PRECISION NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_bet_client FOREIGN KEY (client_id) REFERENCES clients(guid)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_bets_client ON "public"."bets" (client_id, created_at DESC);
@@ -0,0 +1,39 @@
+\c game
+
+CREATE TABLE IF NOT EXISTS "public"."wallet_ledger" (
+    "guid" UUID PRIMARY KEY,
+    "client_id" UUID NOT NULL,
+    "entry_type" VARCHAR(20) NOT NULL,
+    "amount" DOUBLE PRECISION NOT NULL,
+    "balance_after" DOUBLE PRECISION NOT NULL,
+    "reference_id" UUID NOT NULL,
+    "reason" TEXT,
+    "created_by" UUID,
+    "approved_by" UUID,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_wallet_ledger_type CHECK (entry_type IN ('bet', 'adjustment')),
+    CONSTRAINT fk_wallet_ledger_client FOREIGN KEY (client_id) REFERENCES clients(guid)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_ledger_client ON "public"."wallet_ledger" (client_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS "public"."balance_adjustments" (
+    "guid" UUID PRIMARY KEY,
+    "client_id" UUID NOT NULL,
+    "amount" DOUBLE PRECISION NOT NULL,
+    "reason" TEXT NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "requested_by" UUID NOT NULL,
+    "decided_by" UUID,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "decided_at" TIMESTAMPTZ,
+    CONSTRAINT chk_balance_adjustment_status CHECK (status IN ('pending', 'applied', 'rejected')),
+    CONSTRAINT chk_balance_adjustment_reason CHECK (LENGTH(TRIM(reason)) > 0),
+    CONSTRAINT fk_balance_adjustment_client FOREIGN KEY (client_id) REFERENCES clients(guid)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_adjustments_status ON "public"."balance_adjustments" (status, created_at);
@@ -0,0 +1,18 @@
+\c game
+
+CREATE TABLE IF NOT EXISTS "public"."api_keys" (
+    "guid" UUID PRIMARY KEY,
+    "client_id" UUID NOT NULL,
+    "name" VARCHAR(60) NOT NULL,
+    "prefix" VARCHAR(16) NOT NULL,
+    "key_hash" VARCHAR(64) UNIQUE NOT NULL,
+    "scopes" TEXT[] NOT NULL,
+    "rate_limit" INTEGER NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "revoked_at" TIMESTAMPTZ,
+    CONSTRAINT fk_api_key_client FOREIGN KEY (client_id) REFERENCES clients(guid)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_client ON "public"."api_keys" (client_id);
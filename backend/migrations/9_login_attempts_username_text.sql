@@ -0,0 +1,6 @@
+\c game
+
+-- o login aceita usernames de qualquer tamanho; com VARCHAR(60) a auditoria
+-- das tentativas com usernames longos falhava
+ALTER TABLE "public"."login_attempts"
+    ALTER COLUMN "username" TYPE TEXT;
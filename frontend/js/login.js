@@ -64,10 +64,11 @@ $(document).ready(function() {
     // Handler do registro
     $('#btnRegister').click(function() {
         const username = $('#registerUsername').val().trim();
+        const email = $('#registerEmail').val().trim();
         const password = $('#registerPassword').val().trim();
         const confirmPassword = $('#confirmPassword').val().trim();
 
-        if (!username || !password || !confirmPassword) {
+        if (!username || !email || !password || !confirmPassword) {
             showError('Por favor, preencha todos os campos');
             return;
         }
@@ -82,8 +83,8 @@ $(document).ready(function() {
             return;
         }
 
-        if (password.length < 6) {
-            showError('A senha deve ter pelo menos 6 caracteres');
+        if (password.length < 8) {
+            showError('A senha deve ter pelo menos 8 caracteres');
             return;
         }
 
@@ -92,13 +93,13 @@ $(document).ready(function() {
             url: CONFIG.API_BASE_URL + CONFIG.ENDPOINTS.register,
             method: 'POST',
             contentType: 'application/json',
-            data: JSON.stringify({ username, password }),
+            data: JSON.stringify({ username, email, password }),
             success: function(response) {
                 showLoading(false);
                 if (response && response.id) {
-                    $('#registerUsername, #registerPassword, #confirmPassword').val('');
+                    $('#registerUsername, #registerEmail, #registerPassword, #confirmPassword').val('');
                     $('#tabLogin').click();
-                    showSuccess('Registro realizado com sucesso! Faça login para continuar.');
+                    showSuccess('Registro realizado com sucesso! Confirme seu e-mail para poder apostar.');
                 } else {
                     showError('Erro ao realizar registro: ' + (response.message || 'Erro desconhecido'));
                 }
@@ -72,7 +72,7 @@ function handleGameMessage(data) {
     showLoading(false);
     
     if (data.error) {
-        showError(data.error);
+        showError(data.error.message || data.error);
         wsManager.send({ action: 'wallet' });
         return;
     }